@@ -16,12 +16,21 @@ package agollo
 
 import (
 	"container/list"
+	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/apolloconfig/agollo/v5/agcache"
 	"github.com/apolloconfig/agollo/v5/agcache/memory"
+	"github.com/apolloconfig/agollo/v5/cluster/ewma"
+	"github.com/apolloconfig/agollo/v5/cluster/leastoutstanding"
+	"github.com/apolloconfig/agollo/v5/cluster/random"
 	"github.com/apolloconfig/agollo/v5/cluster/roundrobin"
+	"github.com/apolloconfig/agollo/v5/cluster/weighted"
 	"github.com/apolloconfig/agollo/v5/component"
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/component/notify"
@@ -31,12 +40,17 @@ import (
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	jsonFile "github.com/apolloconfig/agollo/v5/env/file/json"
+	"github.com/apolloconfig/agollo/v5/env/server"
 	"github.com/apolloconfig/agollo/v5/extension"
 	"github.com/apolloconfig/agollo/v5/protocol/auth/sign"
 	"github.com/apolloconfig/agollo/v5/storage"
+	"github.com/apolloconfig/agollo/v5/tracing"
 	"github.com/apolloconfig/agollo/v5/utils"
+	jsonParser "github.com/apolloconfig/agollo/v5/utils/parse/json"
 	"github.com/apolloconfig/agollo/v5/utils/parse/normal"
 	"github.com/apolloconfig/agollo/v5/utils/parse/properties"
+	"github.com/apolloconfig/agollo/v5/utils/parse/toml"
+	xmlParser "github.com/apolloconfig/agollo/v5/utils/parse/xml"
 	"github.com/apolloconfig/agollo/v5/utils/parse/yaml"
 	"github.com/apolloconfig/agollo/v5/utils/parse/yml"
 )
@@ -49,15 +63,23 @@ func init() {
 	extension.SetFileHandler(&jsonFile.FileHandler{})
 	extension.SetHTTPAuth(&sign.AuthSignature{})
 
+	// load balance
+	extension.AddLoadBalance(constant.RoundRobin, &roundrobin.RoundRobin{})
+	extension.AddLoadBalance(constant.Random, &random.Random{})
+	extension.AddLoadBalance(constant.Weighted, &weighted.Weighted{})
+	extension.AddLoadBalance(constant.LeastOutstanding, &leastoutstanding.LeastOutstanding{})
+	extension.AddLoadBalance(constant.EWMA, &ewma.EWMA{})
+
 	// file parser
 	extension.AddFormatParser(constant.DEFAULT, &normal.Parser{})
 	extension.AddFormatParser(constant.Properties, &properties.Parser{})
 	extension.AddFormatParser(constant.YML, &yml.Parser{})
 	extension.AddFormatParser(constant.YAML, &yaml.Parser{})
+	extension.AddFormatParser(constant.TOML, &toml.Parser{})
+	extension.AddFormatParser(constant.JSON, &jsonParser.Parser{})
+	extension.AddFormatParser(constant.XML, &xmlParser.Parser{})
 }
 
-var syncApolloConfig = remote.CreateSyncApolloConfig()
-
 // Client apollo 客户端接口
 type Client interface {
 	GetConfig(namespace string) *storage.Config
@@ -72,11 +94,29 @@ type Client interface {
 	GetBoolValue(key string, defaultValue bool) bool
 	GetStringSliceValue(key string, defaultValue []string) []string
 	GetIntSliceValue(key string, defaultValue []int) []int
+	GetDurationValue(key string, defaultValue time.Duration) time.Duration
+	GetTimeValue(key string, layout string, defaultValue time.Time) time.Time
+	GetBytesValue(key string, defaultValue int64) int64
+	GetStringMapValue(prefix string, defaultValue map[string]interface{}) map[string]interface{}
+	GetSubConfig(prefix string) map[string]interface{}
+	Lookup(key string) (interface{}, error)
+	GetString(key string) (string, error)
+	GetInt(key string) (int, error)
+	GetFloat(key string) (float64, error)
+	GetBool(key string) (bool, error)
+	GetDuration(key string) (time.Duration, error)
+	GetTime(key string, layout string) (time.Time, error)
+	GetBytes(key string) (int64, error)
+	Bind(namespace string, v interface{}) error
 	AddChangeListener(listener storage.ChangeListener)
 	RemoveChangeListener(listener storage.ChangeListener)
 	GetChangeListeners() *list.List
 	UseEventDispatch()
 	Close()
+	Shutdown(ctx context.Context) error
+	Start(ctx context.Context) error
+	Subscribe(namespace string) error
+	Unsubscribe(namespace string) error
 }
 
 // internalClient apollo 客户端实例
@@ -85,16 +125,41 @@ type internalClient struct {
 	appConfig         *config.AppConfig
 	cache             *storage.Cache
 	components        []component.Stoppable
+	// extensions 客户端独占的节点列表与组件
+	extensions       *env.Extensions
+	syncApolloConfig remote.ApolloConfig
+	started          atomic.Bool
+	// appConfigLock 保护 appConfig.NamespaceName、components、configComponent 与 unsubscribed
+	appConfigLock   sync.RWMutex
+	configComponent *notify.ConfigComponent
+	// unsubscribed 通过 Unsubscribe 取消订阅的 namespace，GetConfigAndInit 不再自动订阅，重新调用 Subscribe 后恢复
+	unsubscribed map[string]bool
 }
 
 func (c *internalClient) getAppConfig() config.AppConfig {
+	c.appConfigLock.RLock()
+	defer c.appConfigLock.RUnlock()
 	return *c.appConfig
 }
 
 func create() *internalClient {
-	appConfig := env.InitFileConfig()
+	return newClient(env.InitFileConfig(), nil)
+}
+
+// newClient 创建未启动的客户端，extensions 未指定节点列表时使用独立的节点列表
+func newClient(appConfig *config.AppConfig, extensions *env.Extensions) *internalClient {
+	if extensions == nil {
+		extensions = env.NewExtensions()
+	} else if extensions.Servers == nil {
+		// 未指定节点列表时也不与其他客户端共享
+		e := *extensions
+		e.Servers = server.NewServers()
+		extensions = &e
+	}
 	return &internalClient{
-		appConfig: appConfig,
+		appConfig:        appConfig,
+		extensions:       extensions,
+		syncApolloConfig: remote.CreateSyncApolloConfigWithExtensions(extensions),
 	}
 }
 
@@ -105,44 +170,81 @@ func Start() (Client, error) {
 
 // StartWithConfig 根据配置启动
 func StartWithConfig(loadAppConfig func() (*config.AppConfig, error)) (Client, error) {
-	// 有了配置之后才能进行初始化
-	appConfig, err := env.InitConfig(loadAppConfig)
+	return StartWithContext(context.Background(), loadAppConfig)
+}
+
+// StartWithContext 根据配置启动，ctx 用于控制首次同步与服务列表拉取的耗时，
+// ctx 取消或超时将停止已启动的组件并返回 ctx.Err()；
+// 启动成功后的长轮询不受 ctx 影响，由 Close 或 Shutdown 停止
+func StartWithContext(ctx context.Context, loadAppConfig func() (*config.AppConfig, error)) (Client, error) {
+	return StartWithExtensions(ctx, loadAppConfig, nil)
+}
+
+// StartWithExtensions 根据配置启动，extensions 中设置的组件仅对该客户端生效，未设置的使用 extension 包中的全局组件；
+// 每个客户端都使用独立的节点列表，同一进程中可以启动多个不同 appId 或环境的客户端
+func StartWithExtensions(ctx context.Context, loadAppConfig func() (*config.AppConfig, error), extensions *env.Extensions) (Client, error) {
+	c, err := New(WithAppConfigLoader(loadAppConfig), WithExtensions(extensions))
 	if err != nil {
 		return nil, err
 	}
-
-	c := create()
-	if appConfig != nil {
-		c.appConfig = appConfig
+	if err = c.Start(ctx); err != nil {
+		return nil, err
 	}
+	return c, nil
+}
 
-	c.cache = storage.CreateNamespaceConfig(appConfig.NamespaceName)
+// Start 启动客户端：拉取服务列表、首次同步配置并开启长轮询，每个客户端只能启动一次；
+// ctx 用于控制首次同步与服务列表拉取的耗时，ctx 取消或超时将停止已启动的组件并返回 ctx.Err()
+func (c *internalClient) Start(ctx context.Context) (err error) {
+	if !c.started.CompareAndSwap(false, true) {
+		return errors.New("agollo client already started")
+	}
+	ctx, span := extension.GetTracer().Start(ctx, tracing.SpanStart)
+	defer func() {
+		span.End(err)
+	}()
+
+	c.appConfigLock.Lock()
+	appConfig := c.appConfig
+	span.SetAttributes(tracing.String(tracing.AppID, appConfig.AppID),
+		tracing.String(tracing.Cluster, appConfig.Cluster),
+		tracing.String(tracing.Namespace, appConfig.NamespaceName))
 	appConfig.Init()
+	c.appConfigLock.Unlock()
+	c.extensions.GetServers().SetNextTryConnectPeriod(appConfig.GetNextTryConnectPeriod())
 	// start ipList component
-	serverIPListComponent := serverlist.NewSyncServerIPListComponent(c.getAppConfig)
+	serverIPListComponent := serverlist.NewSyncServerIPListComponentWithExtensions(c.getAppConfig, c.extensions)
 	go component.StartRefreshConfig(serverIPListComponent)
 	c.appendComponent(serverIPListComponent)
 
 	//first sync
-	configs := syncApolloConfig.Sync(c.getAppConfig)
-	if len(configs) == 0 && appConfig != nil && appConfig.MustStart {
-		return nil, errors.New("start failed cause no config was read")
+	configs := c.syncApolloConfig.SyncContext(ctx, c.getAppConfig)
+	if ctx.Err() != nil {
+		c.Close()
+		return ctx.Err()
+	}
+	if len(configs) == 0 && appConfig.MustStart {
+		c.Close()
+		return errors.New("start failed cause no config was read")
 	}
 
 	for _, apolloConfig := range configs {
 		c.cache.UpdateApolloConfig(apolloConfig, c.getAppConfig)
 	}
 
-	log.Debug("init notifySyncConfigServices finished")
+	appConfig.GetLogger().Log(log.LevelDebug, "init notifySyncConfigServices finished")
 
 	//start long poll sync config
-	configComponent := notify.NewConfigComponent(c.getAppConfig, c.cache)
+	configComponent := notify.NewConfigComponentWithExtensions(c.getAppConfig, c.cache, c.extensions)
+	c.appConfigLock.Lock()
+	c.configComponent = configComponent
+	c.appConfigLock.Unlock()
 	go component.StartRefreshConfig(configComponent)
 	c.appendComponent(configComponent)
 
-	log.Info("agollo start finished ! ")
+	appConfig.GetLogger().Log(log.LevelInfo, "agollo start finished", log.KV("appId", appConfig.AppID))
 
-	return c, nil
+	return nil
 }
 
 // GetConfig 根据namespace获取apollo配置
@@ -150,7 +252,8 @@ func (c *internalClient) GetConfig(namespace string) *storage.Config {
 	return c.GetConfigAndInit(namespace)
 }
 
-// GetConfigAndInit 根据namespace获取apollo配置
+// GetConfigAndInit 根据namespace获取apollo配置，未订阅的 namespace 会同步并自动订阅，
+// 通过 Unsubscribe 取消订阅的 namespace 返回 nil，需要重新调用 Subscribe
 func (c *internalClient) GetConfigAndInit(namespace string) *storage.Config {
 	if namespace == "" {
 		return nil
@@ -158,11 +261,12 @@ func (c *internalClient) GetConfigAndInit(namespace string) *storage.Config {
 
 	cfg := c.cache.GetConfig(namespace)
 
-	if cfg == nil {
+	if cfg == nil && !c.isUnsubscribed(namespace) {
 		//sync config
-		apolloConfig, _ := syncApolloConfig.SyncWithNamespace(namespace, c.getAppConfig)
+		apolloConfig, _ := c.syncApolloConfig.SyncWithNamespace(namespace, c.getAppConfig)
 		if apolloConfig != nil {
 			c.SyncAndUpdate(namespace, apolloConfig)
+			c.wakeLongPoll()
 		}
 	}
 
@@ -172,26 +276,109 @@ func (c *internalClient) GetConfigAndInit(namespace string) *storage.Config {
 }
 
 func (c *internalClient) SyncAndUpdate(namespace string, apolloConfig *config.ApolloConfig) {
+	c.appConfigLock.Lock()
 	// update appConfig only if namespace does not exist yet
-	namespaces := strings.Split(c.appConfig.NamespaceName, ",")
-	exists := false
-	for _, n := range namespaces {
-		if n == namespace {
-			exists = true
-			break
-		}
-	}
-	if !exists {
-		c.appConfig.NamespaceName += "," + namespace
+	if !c.appConfig.ContainsNamespace(namespace) {
+		c.appConfig.NamespaceName += config.Comma + namespace
 	}
 
-	// update notification
-	c.appConfig.GetNotificationsMap().UpdateNotify(namespace, 0)
+	// 更新 notification，未启动的客户端在 Start 时初始化
+	if notificationsMap := c.appConfig.GetNotificationsMap(); notificationsMap != nil {
+		notificationsMap.UpdateNotify(namespace, 0)
+	}
+	c.appConfigLock.Unlock()
 
 	// update cache
 	c.cache.UpdateApolloConfig(apolloConfig, c.getAppConfig)
 }
 
+// Subscribe 订阅 namespace，同步成功后加入长轮询，已订阅时直接返回；
+// 未启动的客户端只记录 namespace，在 Start 时同步
+func (c *internalClient) Subscribe(namespace string) error {
+	if namespace == "" {
+		return errors.New("subscribe fail! namespace can not be empty")
+	}
+	c.appConfigLock.Lock()
+	delete(c.unsubscribed, namespace)
+	if c.appConfig.ContainsNamespace(namespace) {
+		c.appConfigLock.Unlock()
+		return nil
+	}
+	if !c.started.Load() {
+		c.appConfig.NamespaceName += config.Comma + namespace
+		c.appConfigLock.Unlock()
+		return nil
+	}
+	c.appConfigLock.Unlock()
+
+	apolloConfig, err := c.syncApolloConfig.SyncWithNamespace(namespace, c.getAppConfig)
+	if err != nil {
+		return err
+	}
+	if apolloConfig == nil {
+		return fmt.Errorf("subscribe fail! namespace:%s not found", namespace)
+	}
+	c.SyncAndUpdate(namespace, apolloConfig)
+	c.wakeLongPoll()
+	return nil
+}
+
+// Unsubscribe 取消订阅 namespace，停止长轮询该 namespace，
+// 并移除其配置缓存与只监听该 namespace 的 storage.NamespaceChangeListener；
+// 取消订阅后 GetConfig 等方法不会自动重新订阅该 namespace；
+// GetValue 等方法读取默认 namespace，默认 namespace 不能取消订阅
+func (c *internalClient) Unsubscribe(namespace string) error {
+	if namespace == storage.GetDefaultNamespace() {
+		return fmt.Errorf("unsubscribe fail! namespace:%s is the default namespace", namespace)
+	}
+	c.appConfigLock.Lock()
+	if !c.appConfig.ContainsNamespace(namespace) {
+		c.appConfigLock.Unlock()
+		return fmt.Errorf("unsubscribe fail! namespace:%s not subscribed", namespace)
+	}
+	namespaces := make([]string, 0)
+	for _, n := range strings.Split(c.appConfig.NamespaceName, config.Comma) {
+		if n != namespace {
+			namespaces = append(namespaces, n)
+		}
+	}
+	if len(namespaces) == 0 {
+		c.appConfigLock.Unlock()
+		return fmt.Errorf("unsubscribe fail! namespace:%s is the last subscribed namespace", namespace)
+	}
+	c.appConfig.NamespaceName = strings.Join(namespaces, config.Comma)
+	if c.unsubscribed == nil {
+		c.unsubscribed = make(map[string]bool)
+	}
+	c.unsubscribed[namespace] = true
+	configComponent := c.configComponent
+	c.appConfigLock.Unlock()
+
+	if configComponent == nil {
+		c.cache.RemoveNamespace(namespace)
+		return nil
+	}
+	configComponent.RemoveNamespace(namespace)
+	return nil
+}
+
+// isUnsubscribed 判断 namespace 是否通过 Unsubscribe 取消了订阅
+func (c *internalClient) isUnsubscribed(namespace string) bool {
+	c.appConfigLock.RLock()
+	defer c.appConfigLock.RUnlock()
+	return c.unsubscribed[namespace]
+}
+
+// wakeLongPoll 取消正在进行的长轮询并立即使用最新的 namespace 列表重新发起
+func (c *internalClient) wakeLongPoll() {
+	c.appConfigLock.RLock()
+	configComponent := c.configComponent
+	c.appConfigLock.RUnlock()
+	if configComponent != nil {
+		configComponent.Wake()
+	}
+}
+
 // GetConfigCache 根据namespace获取apollo配置的缓存
 func (c *internalClient) GetConfigCache(namespace string) agcache.CacheInterface {
 	config := c.GetConfigAndInit(namespace)
@@ -251,6 +438,80 @@ func (c *internalClient) GetIntSliceValue(key string, defaultValue []int) []int
 	return c.GetConfig(storage.GetDefaultNamespace()).GetIntSliceValue(key, separator, defaultValue)
 }
 
+// GetDurationValue 获取time.Duration 配置值
+func (c *internalClient) GetDurationValue(key string, defaultValue time.Duration) time.Duration {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetDurationValue(key, defaultValue)
+}
+
+// GetTimeValue 获取time.Time 配置值
+func (c *internalClient) GetTimeValue(key string, layout string, defaultValue time.Time) time.Time {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetTimeValue(key, layout, defaultValue)
+}
+
+// GetBytesValue 获取字节数配置值，如 64MB
+func (c *internalClient) GetBytesValue(key string, defaultValue int64) int64 {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetBytesValue(key, defaultValue)
+}
+
+// GetStringMapValue 获取 prefix. 下的所有配置项
+func (c *internalClient) GetStringMapValue(prefix string, defaultValue map[string]interface{}) map[string]interface{} {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetStringMapValue(prefix, defaultValue)
+}
+
+// GetSubConfig 获取 prefix. 下的所有配置项并组装为嵌套 map
+func (c *internalClient) GetSubConfig(prefix string) map[string]interface{} {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetSubConfig(prefix)
+}
+
+// Lookup 获取配置值，获取失败时返回错误，不等待初始化完成，规则见 storage.Config.Lookup
+func (c *internalClient) Lookup(key string) (interface{}, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).Lookup(key)
+}
+
+// GetString 获取string配置值，获取失败时返回错误
+func (c *internalClient) GetString(key string) (string, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetString(key)
+}
+
+// GetInt 获取int配置值，获取失败时返回错误
+func (c *internalClient) GetInt(key string) (int, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetInt(key)
+}
+
+// GetFloat 获取float配置值，获取失败时返回错误
+func (c *internalClient) GetFloat(key string) (float64, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetFloat(key)
+}
+
+// GetBool 获取bool配置值，获取失败时返回错误
+func (c *internalClient) GetBool(key string) (bool, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetBool(key)
+}
+
+// GetDuration 获取time.Duration配置值，获取失败时返回错误
+func (c *internalClient) GetDuration(key string) (time.Duration, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetDuration(key)
+}
+
+// GetTime 获取time.Time配置值，获取失败时返回错误
+func (c *internalClient) GetTime(key string, layout string) (time.Time, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetTime(key, layout)
+}
+
+// GetBytes 获取字节数配置值，获取失败时返回错误
+func (c *internalClient) GetBytes(key string) (int64, error) {
+	return c.GetConfig(storage.GetDefaultNamespace()).GetBytes(key)
+}
+
+// Bind 将namespace的配置解析到结构体v中，规则见 storage.Config.Unmarshal
+func (c *internalClient) Bind(namespace string, v interface{}) error {
+	cfg := c.GetConfig(namespace)
+	if cfg == nil {
+		return fmt.Errorf("bind fail! namespace:%s not found", namespace)
+	}
+	return cfg.Unmarshal(v)
+}
+
 func (c *internalClient) getConfigValue(key string) interface{} {
 	cache := c.GetDefaultConfigCache()
 	if cache == nil {
@@ -259,7 +520,8 @@ func (c *internalClient) getConfigValue(key string) interface{} {
 
 	value, err := cache.Get(key)
 	if err != nil {
-		log.Errorf("get config value fail! key:%s, error:%v", key, err)
+		appConfig := c.getAppConfig()
+		appConfig.GetLogger().Log(log.LevelError, "get config value fail", log.KV("key", key), log.KV("error", err))
 		return utils.Empty
 	}
 
@@ -267,9 +529,18 @@ func (c *internalClient) getConfigValue(key string) interface{} {
 }
 
 func (c *internalClient) appendComponent(comp component.Stoppable) {
+	c.appConfigLock.Lock()
+	defer c.appConfigLock.Unlock()
 	c.components = append(c.components, comp)
 }
 
+// getComponents 获取已启动组件的快照
+func (c *internalClient) getComponents() []component.Stoppable {
+	c.appConfigLock.RLock()
+	defer c.appConfigLock.RUnlock()
+	return append([]component.Stoppable(nil), c.components...)
+}
+
 // AddChangeListener 增加变更监控
 func (c *internalClient) AddChangeListener(listener storage.ChangeListener) {
 	c.cache.AddChangeListener(listener)
@@ -292,7 +563,24 @@ func (c *internalClient) UseEventDispatch() {
 
 // Close stop components
 func (c *internalClient) Close() {
-	for _, comp := range c.components {
+	for _, comp := range c.getComponents() {
 		comp.Stop()
 	}
 }
+
+// Shutdown 停止组件并等待其退出，ctx 结束时返回 ctx.Err()
+func (c *internalClient) Shutdown(ctx context.Context) error {
+	c.Close()
+	for _, comp := range c.getComponents() {
+		w, ok := comp.(component.Waitable)
+		if !ok {
+			continue
+		}
+		select {
+		case <-w.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -15,11 +15,14 @@
 package agollo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -198,6 +201,55 @@ func TestGetStringValue(t *testing.T) {
 	Assert(t, "value", Equal(v))
 }
 
+func TestGetDurationTimeBytesValue(t *testing.T) {
+	client := createMockApolloConfig(120)
+	client.cache.UpdateApolloConfigCache(map[string]interface{}{
+		"timeout": "1m30s",
+		"startAt": "2021-07-01",
+		"maxSize": "64MB",
+	}, 120, storage.GetDefaultNamespace())
+
+	Assert(t, client.GetDurationValue("timeout", time.Second), Equal(90*time.Second))
+	Assert(t, client.GetDurationValue("joe", time.Second), Equal(time.Second))
+
+	Assert(t, client.GetTimeValue("startAt", "2006-01-02", time.Time{}), Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+	Assert(t, client.GetTimeValue("startAt", time.RFC3339, time.Time{}).IsZero(), Equal(true))
+
+	Assert(t, client.GetBytesValue("maxSize", 1), Equal(int64(64<<20)))
+	Assert(t, client.GetBytesValue("joe", 1), Equal(int64(1)))
+}
+
+func TestGetStringMapValue(t *testing.T) {
+	client := createMockApolloConfig(120)
+	client.cache.UpdateApolloConfigCache(map[string]interface{}{
+		"redis.host":      "127.0.0.1",
+		"redis.pool.size": 10,
+	}, 120, storage.GetDefaultNamespace())
+
+	Assert(t, client.GetStringMapValue("redis", nil), Equal(map[string]interface{}{
+		"host":      "127.0.0.1",
+		"pool.size": 10,
+	}))
+	Assert(t, client.GetSubConfig("redis"), Equal(map[string]interface{}{
+		"host": "127.0.0.1",
+		"pool": map[string]interface{}{"size": 10},
+	}))
+}
+
+func TestLookup(t *testing.T) {
+	client := createMockApolloConfig(120)
+
+	v, err := client.GetInt("int")
+	Assert(t, err, NilVal())
+	Assert(t, v, Equal(1))
+
+	_, err = client.GetInt("float")
+	Assert(t, errors.Is(err, storage.ErrConvertFailed), Equal(true))
+
+	_, err = client.Lookup("joe")
+	Assert(t, errors.Is(err, storage.ErrKeyNotFound), Equal(true))
+}
+
 func TestAutoSyncConfigServicesNormal2NotModified(t *testing.T) {
 	client := createMockApolloConfig(120)
 	serverResponse := runLongNotmodifiedConfigResponse()
@@ -356,7 +408,7 @@ func TestUseEventDispatch(t *testing.T) {
 
 func TestGetConfigAndInitValNotNil(t *testing.T) {
 	var apc *remote.AbsApolloConfig
-	patch := gomonkey.ApplyMethod(reflect.TypeOf(apc), "SyncWithNamespace", func(_ *remote.AbsApolloConfig, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
+	patch := gomonkey.ApplyMethod(reflect.TypeOf(apc), "SyncWithNamespaceContext", func(_ *remote.AbsApolloConfig, _ context.Context, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
 		return &config.ApolloConfig{
 			ApolloConnConfig: config.ApolloConnConfig{
 				AppID:         "testID",
@@ -380,7 +432,7 @@ func TestGetConfigAndInitValNotNil(t *testing.T) {
 	patch.Reset()
 
 	// second replace
-	patch1 := gomonkey.ApplyMethod(reflect.TypeOf(apc), "SyncWithNamespace", func(_ *remote.AbsApolloConfig, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
+	patch1 := gomonkey.ApplyMethod(reflect.TypeOf(apc), "SyncWithNamespaceContext", func(_ *remote.AbsApolloConfig, _ context.Context, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
 		return &config.ApolloConfig{
 			ApolloConnConfig: config.ApolloConnConfig{
 				AppID:         "testID",
@@ -400,7 +452,7 @@ func TestGetConfigAndInitValNotNil(t *testing.T) {
 
 func TestGetConfigAndInitValNil(t *testing.T) {
 	var apc *remote.AbsApolloConfig
-	patch := gomonkey.ApplyMethod(reflect.TypeOf(apc), "SyncWithNamespace", func(_ *remote.AbsApolloConfig, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
+	patch := gomonkey.ApplyMethod(reflect.TypeOf(apc), "SyncWithNamespaceContext", func(_ *remote.AbsApolloConfig, _ context.Context, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
 		return nil, nil
 	})
 	defer patch.Reset()
@@ -412,14 +464,14 @@ func TestGetConfigAndInitValNil(t *testing.T) {
 }
 
 type testComponent struct {
-	status int // 0 start 1 stop
+	status int32 // 0 start 1 stop
 }
 
 func (t *testComponent) Start() {
-	t.status = 0
+	atomic.StoreInt32(&t.status, 0)
 }
 func (t *testComponent) Stop() {
-	t.status = 1
+	atomic.StoreInt32(&t.status, 1)
 }
 
 func Test_internalClient_Close(t *testing.T) {
@@ -432,10 +484,69 @@ func Test_internalClient_Close(t *testing.T) {
 	go component.StartRefreshConfig(tc2)
 	c.appendComponent(tc2)
 	time.Sleep(300 * time.Millisecond) // wait goroutine
-	Assert(t, tc.status, Equal(0))
-	Assert(t, tc2.status, Equal(0))
+	Assert(t, atomic.LoadInt32(&tc.status), Equal(int32(0)))
+	Assert(t, atomic.LoadInt32(&tc2.status), Equal(int32(0)))
 	c.Close()
 	c.Close() // duplicate Close
-	Assert(t, tc.status, Equal(1))
-	Assert(t, tc2.status, Equal(1))
+	Assert(t, atomic.LoadInt32(&tc.status), Equal(int32(1)))
+	Assert(t, atomic.LoadInt32(&tc2.status), Equal(int32(1)))
+}
+
+func Test_internalClient_CloseWhileAppending(t *testing.T) {
+	c := &internalClient{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.appendComponent(&testComponent{})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		c.Close()
+	}
+	<-done
+	c.Close()
+	for _, comp := range c.getComponents() {
+		Assert(t, atomic.LoadInt32(&comp.(*testComponent).status), Equal(int32(1)))
+	}
+}
+
+func TestBind(t *testing.T) {
+	client := createMockApolloConfig(120)
+
+	var cfg struct {
+		String      string   `apollo:"string"`
+		Int         int      `apollo:"int"`
+		Float       float64  `apollo:"float"`
+		Bool        bool     `apollo:"bool"`
+		StringSlice []string `apollo:"stringSlice"`
+		IntSlice    []int    `apollo:"intSlice"`
+		Default     string   `apollo:"joe" default:"j"`
+	}
+	err := client.Bind(storage.GetDefaultNamespace(), &cfg)
+	Assert(t, err, NilVal())
+	Assert(t, cfg.String, Equal("value"))
+	Assert(t, cfg.Int, Equal(1))
+	Assert(t, cfg.Float, Equal(190.3))
+	Assert(t, cfg.Bool, Equal(true))
+	Assert(t, cfg.StringSlice, Equal([]string{"1", "2"}))
+	Assert(t, cfg.IntSlice, Equal([]int{1, 2}))
+	Assert(t, cfg.Default, Equal("j"))
+
+	err = client.Bind("", &cfg)
+	Assert(t, err, NotNilVal())
+}
+
+func TestWatchClient(t *testing.T) {
+	client := createMockApolloConfig(120)
+
+	type watchConfig struct {
+		String string `apollo:"string"`
+		Int    int    `apollo:"int"`
+	}
+	w, err := storage.Watch[watchConfig](client, storage.GetDefaultNamespace())
+	Assert(t, err, NilVal())
+	defer w.Close()
+	Assert(t, w.Load(), Equal(watchConfig{String: "value", Int: 1}))
+	Assert(t, client.GetChangeListeners().Len(), Equal(1))
 }
@@ -0,0 +1,111 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ewma
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/server"
+)
+
+const (
+	//defaultDecay 新观测值的权重
+	defaultDecay = 0.3
+	//defaultErrorPenalty 请求失败时记录的最小耗时
+	defaultErrorPenalty = 5 * time.Second
+)
+
+// EWMA 选择响应时间指数加权移动平均值最小的节点，没有观测值的节点优先选择，相同时随机选择
+type EWMA struct {
+	// Decay 新观测值的权重，取值 (0, 1]，默认 0.3
+	Decay float64
+	// ErrorPenalty 请求失败时记录的最小耗时，默认 5s
+	ErrorPenalty time.Duration
+
+	lock sync.Mutex
+	// homepageURL -> average cost
+	average map[string]time.Duration
+}
+
+// Load 负载均衡
+func (e *EWMA) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
+	candidates := cluster.SortedServers(servers)
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	e.lock.Lock()
+	costs := make(map[string]time.Duration, len(candidates))
+	for _, info := range candidates {
+		costs[info.HomepageURL] = e.average[info.HomepageURL]
+	}
+	e.lock.Unlock()
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return costs[candidates[i].HomepageURL] < costs[candidates[j].HomepageURL]
+	})
+	for _, info := range candidates {
+		if server.AllowRequest(info) {
+			return info
+		}
+	}
+	return nil
+}
+
+// OnRequestStart 开始请求节点
+func (e *EWMA) OnRequestStart(homepageURL string) {
+}
+
+// OnRequestDone 更新节点的平均响应时间
+func (e *EWMA) OnRequestDone(homepageURL string, cost time.Duration, err error) {
+	if err != nil {
+		penalty := e.ErrorPenalty
+		if penalty <= 0 {
+			penalty = defaultErrorPenalty
+		}
+		if cost < penalty {
+			cost = penalty
+		}
+	}
+	decay := e.Decay
+	if decay <= 0 || decay > 1 {
+		decay = defaultDecay
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.average == nil {
+		e.average = make(map[string]time.Duration)
+	}
+	average, ok := e.average[homepageURL]
+	if !ok {
+		e.average[homepageURL] = cost
+		return
+	}
+	e.average[homepageURL] = time.Duration(decay*float64(cost) + (1-decay)*float64(average))
+}
+
+// GetAverage 获取节点的平均响应时间
+func (e *EWMA) GetAverage(homepageURL string) (time.Duration, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	average, ok := e.average[homepageURL]
+	return average, ok
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ewma
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+var (
+	ewmaLoadBalance cluster.LoadBalance     = &EWMA{}
+	ewmaObserver    cluster.RequestObserver = &EWMA{}
+)
+
+func newServers(urls ...string) map[string]*config.ServerInfo {
+	servers := make(map[string]*config.ServerInfo, len(urls))
+	for _, u := range urls {
+		servers[u] = &config.ServerInfo{HomepageURL: u}
+	}
+	return servers
+}
+
+func TestEWMA_OnRequestDone(t *testing.T) {
+	e := &EWMA{Decay: 0.5}
+
+	_, ok := e.GetAverage("a")
+	Assert(t, ok, Equal(false))
+
+	e.OnRequestDone("a", 100*time.Millisecond, nil)
+	average, ok := e.GetAverage("a")
+	Assert(t, ok, Equal(true))
+	Assert(t, average, Equal(100*time.Millisecond))
+
+	e.OnRequestDone("a", 200*time.Millisecond, nil)
+	average, _ = e.GetAverage("a")
+	Assert(t, average, Equal(150*time.Millisecond))
+
+	e.OnRequestDone("b", 10*time.Millisecond, errors.New("fail"))
+	average, _ = e.GetAverage("b")
+	Assert(t, average, Equal(defaultErrorPenalty))
+
+	ewmaObserver.OnRequestStart("a")
+	ewmaObserver.OnRequestDone("a", time.Millisecond, nil)
+}
+
+func TestEWMA_Load(t *testing.T) {
+	e := &EWMA{}
+	servers := newServers("a", "b", "c")
+
+	e.OnRequestDone("a", 300*time.Millisecond, nil)
+	e.OnRequestDone("b", 100*time.Millisecond, nil)
+	// 没有观测值的节点优先
+	Assert(t, e.Load(servers).HomepageURL, Equal("c"))
+
+	e.OnRequestDone("c", 200*time.Millisecond, nil)
+	Assert(t, e.Load(servers).HomepageURL, Equal("b"))
+
+	servers["b"].IsDown = true
+	Assert(t, e.Load(servers).HomepageURL, Equal("c"))
+
+	servers["a"].IsDown = true
+	servers["c"].IsDown = true
+	Assert(t, ewmaLoadBalance.Load(servers), NilVal())
+}
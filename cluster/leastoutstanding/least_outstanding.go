@@ -0,0 +1,79 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leastoutstanding
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/server"
+)
+
+// LeastOutstanding 选择进行中请求最少的节点，请求数相同时随机选择
+type LeastOutstanding struct {
+	lock sync.Mutex
+	// homepageURL -> outstanding requests
+	outstanding map[string]int
+}
+
+// Load 负载均衡
+func (l *LeastOutstanding) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
+	candidates := cluster.SortedServers(servers)
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	l.lock.Lock()
+	counts := make(map[string]int, len(candidates))
+	for _, info := range candidates {
+		counts[info.HomepageURL] = l.outstanding[info.HomepageURL]
+	}
+	l.lock.Unlock()
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return counts[candidates[i].HomepageURL] < counts[candidates[j].HomepageURL]
+	})
+	for _, info := range candidates {
+		if server.AllowRequest(info) {
+			return info
+		}
+	}
+	return nil
+}
+
+// OnRequestStart 进行中请求数加一
+func (l *LeastOutstanding) OnRequestStart(homepageURL string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.outstanding == nil {
+		l.outstanding = make(map[string]int)
+	}
+	l.outstanding[homepageURL]++
+}
+
+// OnRequestDone 进行中请求数减一
+func (l *LeastOutstanding) OnRequestDone(homepageURL string, cost time.Duration, err error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.outstanding[homepageURL] <= 1 {
+		delete(l.outstanding, homepageURL)
+		return
+	}
+	l.outstanding[homepageURL]--
+}
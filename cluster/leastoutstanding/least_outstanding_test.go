@@ -0,0 +1,71 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leastoutstanding
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+var (
+	leastOutstandingLoadBalance cluster.LoadBalance     = &LeastOutstanding{}
+	leastOutstandingObserver    cluster.RequestObserver = &LeastOutstanding{}
+)
+
+func newServers(urls ...string) map[string]*config.ServerInfo {
+	servers := make(map[string]*config.ServerInfo, len(urls))
+	for _, u := range urls {
+		servers[u] = &config.ServerInfo{HomepageURL: u}
+	}
+	return servers
+}
+
+func TestLeastOutstanding_Load(t *testing.T) {
+	l := &LeastOutstanding{}
+	servers := newServers("a", "b", "c")
+
+	l.OnRequestStart("a")
+	l.OnRequestStart("a")
+	l.OnRequestStart("b")
+	Assert(t, l.Load(servers).HomepageURL, Equal("c"))
+
+	l.OnRequestStart("c")
+	l.OnRequestStart("c")
+	Assert(t, l.Load(servers).HomepageURL, Equal("b"))
+
+	l.OnRequestDone("a", 0, nil)
+	l.OnRequestDone("a", 0, nil)
+	Assert(t, l.Load(servers).HomepageURL, Equal("a"))
+
+	servers["a"].IsDown = true
+	Assert(t, l.Load(servers).HomepageURL, Equal("b"))
+
+	servers["b"].IsDown = true
+	servers["c"].IsDown = true
+	Assert(t, leastOutstandingLoadBalance.Load(servers), NilVal())
+}
+
+func TestLeastOutstanding_Done(t *testing.T) {
+	l := &LeastOutstanding{}
+	l.OnRequestDone("a", 0, nil)
+	Assert(t, len(l.outstanding), Equal(0))
+
+	leastOutstandingObserver.OnRequestStart("a")
+	leastOutstandingObserver.OnRequestDone("a", 0, nil)
+}
@@ -15,11 +15,36 @@
 package cluster
 
 import (
+	"sort"
+	"time"
+
 	"github.com/apolloconfig/agollo/v5/env/config"
 )
 
 // LoadBalance 负载均衡器
 type LoadBalance interface {
-	//Load 负载均衡，获取对应服务信息
+	//Load 负载均衡，获取对应服务信息，应通过 server.AllowRequest 跳过熔断的节点
 	Load(servers map[string]*config.ServerInfo) *config.ServerInfo
 }
+
+// RequestObserver 可选接口，LoadBalance 实现后可以获取经其选择的节点的请求结果，长轮询请求不会通知
+type RequestObserver interface {
+	//OnRequestStart 开始请求节点
+	OnRequestStart(homepageURL string)
+	//OnRequestDone 节点请求结束，cost 为请求耗时（含重试）
+	OnRequestDone(homepageURL string, cost time.Duration, err error)
+}
+
+// SortedServers 按节点地址排序，用于需要稳定顺序的负载均衡策略
+func SortedServers(servers map[string]*config.ServerInfo) []*config.ServerInfo {
+	sorted := make([]*config.ServerInfo, 0, len(servers))
+	for _, info := range servers {
+		if info != nil {
+			sorted = append(sorted, info)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].HomepageURL < sorted[j].HomepageURL
+	})
+	return sorted
+}
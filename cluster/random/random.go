@@ -0,0 +1,44 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package random
+
+import (
+	"math/rand"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/server"
+)
+
+// Random 随机选择节点
+type Random struct {
+}
+
+// Load 负载均衡
+func (r *Random) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
+	sorted := cluster.SortedServers(servers)
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	start := rand.Intn(len(sorted))
+	for i := range sorted {
+		info := sorted[(start+i)%len(sorted)]
+		if server.AllowRequest(info) {
+			return info
+		}
+	}
+	return nil
+}
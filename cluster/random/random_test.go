@@ -0,0 +1,54 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package random
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+var randomLoadBalance cluster.LoadBalance = &Random{}
+
+func newServers(urls ...string) map[string]*config.ServerInfo {
+	servers := make(map[string]*config.ServerInfo, len(urls))
+	for _, u := range urls {
+		servers[u] = &config.ServerInfo{HomepageURL: u}
+	}
+	return servers
+}
+
+func TestRandom_Load(t *testing.T) {
+	servers := newServers("http://10.0.0.1:8080/", "http://10.0.0.2:8080/", "http://10.0.0.3:8080/")
+
+	selected := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		selected[randomLoadBalance.Load(servers).HomepageURL]++
+	}
+	Assert(t, len(selected), Equal(3))
+
+	servers["http://10.0.0.1:8080/"].IsDown = true
+	servers["http://10.0.0.2:8080/"].IsDown = true
+	for i := 0; i < 10; i++ {
+		Assert(t, randomLoadBalance.Load(servers).HomepageURL, Equal("http://10.0.0.3:8080/"))
+	}
+
+	servers["http://10.0.0.3:8080/"].IsDown = true
+	Assert(t, randomLoadBalance.Load(servers), NilVal())
+	Assert(t, randomLoadBalance.Load(nil), NilVal())
+}
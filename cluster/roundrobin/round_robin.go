@@ -15,23 +15,32 @@
 package roundrobin
 
 import (
+	"sync/atomic"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
 	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/server"
 )
 
-// RoundRobin 轮询调度
+// RoundRobin 轮询调度，节点按地址排序后依次选择
 type RoundRobin struct {
+	next uint64
 }
 
 // Load 负载均衡
 func (r *RoundRobin) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
-	var returnServer *config.ServerInfo
-	for _, server := range servers {
+	sorted := cluster.SortedServers(servers)
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	start := int((atomic.AddUint64(&r.next, 1) - 1) % uint64(len(sorted)))
+	for i := range sorted {
+		info := sorted[(start+i)%len(sorted)]
 		// if some node has down then select next node
-		if server.IsDown {
-			continue
+		if server.AllowRequest(info) {
+			return info
 		}
-		returnServer = server
-		break
 	}
-	return returnServer
+	return nil
 }
@@ -16,6 +16,7 @@ package roundrobin
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/tevid/gohamcrest"
 
@@ -124,6 +125,46 @@ func TestSelectHost(t *testing.T) {
 	Assert(t, balanace.Load(server.GetServers(appConfig.GetHost())), NilVal())
 }
 
+func TestSelectRecoveredHost(t *testing.T) {
+	server.SetCircuitBreaker(1, 50*time.Millisecond)
+	defer server.SetCircuitBreaker(1, 10*time.Second)
+
+	balanace := &RoundRobin{}
+	configService := "http://recovered:8080/"
+	node := &config.ServerInfo{HomepageURL: "http://10.0.0.10:8080/"}
+	server.SetServers(configService, map[string]*config.ServerInfo{node.HomepageURL: node})
+
+	server.SetDownNode(configService, node.HomepageURL)
+	Assert(t, balanace.Load(server.GetServers(configService)), NilVal())
+
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, balanace.Load(server.GetServers(configService)), Equal(node))
+	server.SetUpNode(configService, node.HomepageURL)
+	Assert(t, balanace.Load(server.GetServers(configService)), Equal(node))
+}
+
+func TestRoundRobinStableOrder(t *testing.T) {
+	balanace := &RoundRobin{}
+	servers := map[string]*config.ServerInfo{
+		"c": {HomepageURL: "http://10.0.0.3:8080/"},
+		"a": {HomepageURL: "http://10.0.0.1:8080/"},
+		"b": {HomepageURL: "http://10.0.0.2:8080/"},
+	}
+
+	selected := make([]string, 0, 6)
+	for i := 0; i < 6; i++ {
+		selected = append(selected, balanace.Load(servers).HomepageURL)
+	}
+	Assert(t, selected, Equal([]string{
+		"http://10.0.0.1:8080/", "http://10.0.0.2:8080/", "http://10.0.0.3:8080/",
+		"http://10.0.0.1:8080/", "http://10.0.0.2:8080/", "http://10.0.0.3:8080/",
+	}))
+
+	servers["b"].IsDown = true
+	Assert(t, balanace.Load(servers).HomepageURL, Equal("http://10.0.0.1:8080/"))
+	Assert(t, balanace.Load(servers).HomepageURL, Equal("http://10.0.0.3:8080/"))
+}
+
 func deleteServers(appConfig *config.AppConfig) {
 	servers := make(map[string]*config.ServerInfo)
 	server.SetServers(appConfig.GetHost(), servers)
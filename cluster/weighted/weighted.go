@@ -0,0 +1,74 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weighted
+
+import (
+	"sync"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/server"
+)
+
+// Weighted 平滑加权轮询，权重取自 ServerInfo.Weight，<=0 时按 1 处理
+type Weighted struct {
+	lock sync.Mutex
+	// homepageURL -> current weight
+	current map[string]int
+}
+
+// Load 负载均衡
+func (w *Weighted) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
+	candidates := cluster.SortedServers(servers)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.current == nil {
+		w.current = make(map[string]int)
+	}
+
+	for len(candidates) > 0 {
+		total := 0
+		best := -1
+		for i, info := range candidates {
+			weight := weightOf(info)
+			w.current[info.HomepageURL] += weight
+			total += weight
+			if best < 0 || w.current[info.HomepageURL] > w.current[candidates[best].HomepageURL] {
+				best = i
+			}
+		}
+
+		info := candidates[best]
+		if server.AllowRequest(info) {
+			w.current[info.HomepageURL] -= total
+			return info
+		}
+
+		// 节点不可用，撤销本轮增加的权重后从候选中移除
+		for _, c := range candidates {
+			w.current[c.HomepageURL] -= weightOf(c)
+		}
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return nil
+}
+
+func weightOf(info *config.ServerInfo) int {
+	if info.Weight <= 0 {
+		return 1
+	}
+	return info.Weight
+}
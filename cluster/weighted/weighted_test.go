@@ -0,0 +1,54 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weighted
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+var weightedLoadBalance cluster.LoadBalance = &Weighted{}
+
+func TestWeighted_Load(t *testing.T) {
+	w := &Weighted{}
+	servers := map[string]*config.ServerInfo{
+		"a": {HomepageURL: "a", Weight: 5},
+		"b": {HomepageURL: "b", Weight: 1},
+		"c": {HomepageURL: "c"},
+	}
+
+	// 平滑加权轮询：a a b a c a a
+	selected := make([]string, 0, 7)
+	for i := 0; i < 7; i++ {
+		selected = append(selected, w.Load(servers).HomepageURL)
+	}
+	Assert(t, selected, Equal([]string{"a", "a", "b", "a", "c", "a", "a"}))
+
+	servers["a"].IsDown = true
+	counts := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		counts[w.Load(servers).HomepageURL]++
+	}
+	Assert(t, counts, Equal(map[string]int{"b": 2, "c": 2}))
+
+	servers["b"].IsDown = true
+	servers["c"].IsDown = true
+	Assert(t, weightedLoadBalance.Load(servers), NilVal())
+	Assert(t, weightedLoadBalance.Load(nil), NilVal())
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zone
+
+import (
+	"strings"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+// LoadBalance 可用区感知的负载均衡器，优先通过 Next 从 Zone 相同的节点中选择，
+// 本可用区的节点都不可用时才选择其他可用区的节点
+type LoadBalance struct {
+	// Zone 客户端所在的可用区，为空时不区分可用区
+	Zone string
+	// Next 实际选择节点的负载均衡器
+	Next cluster.LoadBalance
+}
+
+// Load 负载均衡
+func (l *LoadBalance) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
+	if l.Zone == "" {
+		return l.Next.Load(servers)
+	}
+
+	local := make(map[string]*config.ServerInfo)
+	remote := make(map[string]*config.ServerInfo)
+	for k, info := range servers {
+		if info == nil {
+			continue
+		}
+		if strings.EqualFold(info.Zone, l.Zone) {
+			local[k] = info
+		} else {
+			remote[k] = info
+		}
+	}
+
+	if len(local) > 0 {
+		if info := l.Next.Load(local); info != nil {
+			return info
+		}
+	}
+	if len(remote) == 0 {
+		return nil
+	}
+	return l.Next.Load(remote)
+}
+
+// OnRequestStart 转发给 Next
+func (l *LoadBalance) OnRequestStart(homepageURL string) {
+	if observer, ok := l.Next.(cluster.RequestObserver); ok {
+		observer.OnRequestStart(homepageURL)
+	}
+}
+
+// OnRequestDone 转发给 Next
+func (l *LoadBalance) OnRequestDone(homepageURL string, cost time.Duration, err error) {
+	if observer, ok := l.Next.(cluster.RequestObserver); ok {
+		observer.OnRequestDone(homepageURL, cost, err)
+	}
+}
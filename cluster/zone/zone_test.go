@@ -0,0 +1,94 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zone
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/cluster/roundrobin"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+var (
+	zoneLoadBalance cluster.LoadBalance     = &LoadBalance{}
+	zoneObserver    cluster.RequestObserver = &LoadBalance{}
+)
+
+type testObserver struct {
+	roundrobin.RoundRobin
+	done []string
+}
+
+func (o *testObserver) OnRequestStart(homepageURL string) {
+}
+
+func (o *testObserver) OnRequestDone(homepageURL string, cost time.Duration, err error) {
+	o.done = append(o.done, homepageURL)
+}
+
+func TestLoadBalance_Load(t *testing.T) {
+	servers := map[string]*config.ServerInfo{
+		"a1": {HomepageURL: "a1", Zone: "az-a"},
+		"a2": {HomepageURL: "a2", Zone: "AZ-A"},
+		"b1": {HomepageURL: "b1", Zone: "az-b"},
+		"c1": {HomepageURL: "c1"},
+	}
+	lb := &LoadBalance{Zone: "az-a", Next: &roundrobin.RoundRobin{}}
+
+	for i := 0; i < 10; i++ {
+		info := lb.Load(servers)
+		Assert(t, info.HomepageURL == "a1" || info.HomepageURL == "a2", Equal(true))
+	}
+
+	// 本可用区节点都不可用时选择其他可用区
+	servers["a1"].IsDown = true
+	servers["a2"].IsDown = true
+	for i := 0; i < 10; i++ {
+		info := lb.Load(servers)
+		Assert(t, info.HomepageURL == "b1" || info.HomepageURL == "c1", Equal(true))
+	}
+
+	servers["b1"].IsDown = true
+	servers["c1"].IsDown = true
+	Assert(t, lb.Load(servers), NilVal())
+}
+
+func TestLoadBalance_NoZone(t *testing.T) {
+	servers := map[string]*config.ServerInfo{
+		"a1": {HomepageURL: "a1", Zone: "az-a"},
+	}
+	lb := &LoadBalance{Next: &roundrobin.RoundRobin{}}
+	Assert(t, lb.Load(servers).HomepageURL, Equal("a1"))
+
+	lb.Zone = "az-b"
+	Assert(t, lb.Load(servers).HomepageURL, Equal("a1"))
+	Assert(t, lb.Load(nil), NilVal())
+}
+
+func TestLoadBalance_Observer(t *testing.T) {
+	next := &testObserver{}
+	lb := &LoadBalance{Zone: "az-a", Next: next}
+	lb.OnRequestStart("a1")
+	lb.OnRequestDone("a1", time.Millisecond, nil)
+	Assert(t, next.done, Equal([]string{"a1"}))
+
+	lb = &LoadBalance{Zone: "az-a", Next: &roundrobin.RoundRobin{}}
+	lb.OnRequestStart("a1")
+	lb.OnRequestDone("a1", time.Millisecond, nil)
+}
@@ -14,12 +14,22 @@
 
 package component
 
-import "github.com/apolloconfig/agollo/v5/component/log"
+import (
+	"context"
+
+	"github.com/apolloconfig/agollo/v5/component/log"
+)
 
 type Stoppable interface {
 	Stop()
 }
 
+// Waitable 可等待退出的组件
+type Waitable interface {
+	// Done 组件的 Start 返回后关闭
+	Done() <-chan struct{}
+}
+
 // AbsComponent 定时组件
 type AbsComponent interface {
 	Start()
@@ -34,3 +44,16 @@ func StartRefreshConfig(component AbsComponent) {
 	}()
 	component.Start()
 }
+
+// StopContext 创建在 stopCh 关闭时取消的 context，用于中断组件进行中的请求
+func StopContext(stopCh <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
@@ -47,16 +47,25 @@ type LoggerInterface interface {
 
 // Debugf debug 格式化
 func Debugf(format string, params ...interface{}) {
+	if GetLevel() > LevelDebug {
+		return
+	}
 	Logger.Debugf(format, params...)
 }
 
 // Infof 打印info
 func Infof(format string, params ...interface{}) {
+	if GetLevel() > LevelInfo {
+		return
+	}
 	Logger.Infof(format, params...)
 }
 
 // Warnf warn格式化
 func Warnf(format string, params ...interface{}) {
+	if GetLevel() > LevelWarn {
+		return
+	}
 	Logger.Warnf(format, params...)
 }
 
@@ -67,16 +76,25 @@ func Errorf(format string, params ...interface{}) {
 
 // Debug 打印debug
 func Debug(v ...interface{}) {
+	if GetLevel() > LevelDebug {
+		return
+	}
 	Logger.Debug(v...)
 }
 
 // Info 打印Info
 func Info(v ...interface{}) {
+	if GetLevel() > LevelInfo {
+		return
+	}
 	Logger.Info(v...)
 }
 
 // Warn 打印Warn
 func Warn(v ...interface{}) {
+	if GetLevel() > LevelWarn {
+		return
+	}
 	Logger.Warn(v...)
 }
 
@@ -0,0 +1,118 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/apolloconfig/agollo/v5/component/log"
+)
+
+// Logger 基于 log/slog 的日志实现，同时实现 log.LoggerInterface 与 log.StructuredLogger
+type Logger struct {
+	logger *slog.Logger
+}
+
+// NewLogger 创建日志，logger 为 nil 时使用 slog.Default()
+func NewLogger(logger *slog.Logger) *Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Logger{logger: logger}
+}
+
+// Enabled 判断是否输出 level 级别的日志
+func (l *Logger) Enabled(level log.Level) bool {
+	return l.logger.Enabled(context.Background(), toSlogLevel(level))
+}
+
+// Log 输出结构化日志
+func (l *Logger) Log(level log.Level, msg string, fields ...log.Field) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, slog.Any(field.Key, field.Value))
+	}
+	l.logger.LogAttrs(context.Background(), toSlogLevel(level), msg, attrs...)
+}
+
+// Debugf debug 格式化
+func (l *Logger) Debugf(format string, params ...interface{}) {
+	l.logf(log.LevelDebug, format, params...)
+}
+
+// Infof 打印info
+func (l *Logger) Infof(format string, params ...interface{}) {
+	l.logf(log.LevelInfo, format, params...)
+}
+
+// Warnf warn格式化
+func (l *Logger) Warnf(format string, params ...interface{}) {
+	l.logf(log.LevelWarn, format, params...)
+}
+
+// Errorf error格式化
+func (l *Logger) Errorf(format string, params ...interface{}) {
+	l.logf(log.LevelError, format, params...)
+}
+
+// Debug 打印debug
+func (l *Logger) Debug(v ...interface{}) {
+	l.log(log.LevelDebug, v...)
+}
+
+// Info 打印Info
+func (l *Logger) Info(v ...interface{}) {
+	l.log(log.LevelInfo, v...)
+}
+
+// Warn 打印Warn
+func (l *Logger) Warn(v ...interface{}) {
+	l.log(log.LevelWarn, v...)
+}
+
+// Error 打印Error
+func (l *Logger) Error(v ...interface{}) {
+	l.log(log.LevelError, v...)
+}
+
+func (l *Logger) logf(level log.Level, format string, params ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.logger.Log(context.Background(), toSlogLevel(level), fmt.Sprintf(format, params...))
+}
+
+func (l *Logger) log(level log.Level, v ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.logger.Log(context.Background(), toSlogLevel(level), fmt.Sprint(v...))
+}
+
+func toSlogLevel(level log.Level) slog.Level {
+	switch level {
+	case log.LevelDebug:
+		return slog.LevelDebug
+	case log.LevelInfo:
+		return slog.LevelInfo
+	case log.LevelWarn:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/component/log"
+)
+
+var (
+	_ log.LoggerInterface  = (*Logger)(nil)
+	_ log.StructuredLogger = (*Logger)(nil)
+)
+
+func newTestLogger(level slog.Level) (*Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return NewLogger(slog.New(handler)), buf
+}
+
+func TestLog(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelDebug)
+	logger.Log(log.LevelWarn, "sync fail", log.KV("namespace", "application"), log.KV("status", 500))
+	Assert(t, buf.String(), Equal("level=WARN msg=\"sync fail\" namespace=application status=500\n"))
+}
+
+func TestPrintf(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+	logger.Debugf("debug %s", "message")
+	logger.Debug("debug")
+	Assert(t, buf.Len(), Equal(0))
+
+	logger.Infof("info %s", "message")
+	logger.Warn("warn ", "message")
+	logger.Errorf("error %d", 1)
+	Assert(t, buf.String(), Equal("level=INFO msg=\"info message\"\nlevel=WARN msg=\"warn message\"\nlevel=ERROR msg=\"error 1\"\n"))
+}
+
+func TestEnabled(t *testing.T) {
+	logger, _ := newTestLogger(slog.LevelWarn)
+	Assert(t, logger.Enabled(log.LevelInfo), Equal(false))
+	Assert(t, logger.Enabled(log.LevelError), Equal(true))
+
+	// 与全局日志级别组合
+	filtered := log.WithLevel(logger, log.LevelError)
+	Assert(t, filtered.Enabled(log.LevelWarn), Equal(false))
+	Assert(t, NewLogger(nil).logger, Equal(slog.Default()))
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Level 日志级别
+type Level int32
+
+const (
+	// LevelDebug debug 级别
+	LevelDebug Level = iota
+	// LevelInfo info 级别
+	LevelInfo
+	// LevelWarn warn 级别
+	LevelWarn
+	// LevelError error 级别
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// ParseLevel 解析日志级别，支持 debug、info、warn（warning）、error，不区分大小写
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelDebug, fmt.Errorf("unknown log level: %q", s)
+}
+
+// Field 结构化日志字段
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// KV 创建结构化日志字段
+func KV(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// StructuredLogger 结构化日志接口
+type StructuredLogger interface {
+	// Enabled 判断是否输出 level 级别的日志，拼装日志内容代价较大时应该先判断
+	Enabled(level Level) bool
+	// Log 输出日志，fields 为键值对字段
+	Log(level Level, msg string, fields ...Field)
+}
+
+// NewStructuredLogger 将 printf 风格的 LoggerInterface 适配为 StructuredLogger，
+// 字段以 key=value 的形式拼接在 msg 之后
+func NewStructuredLogger(logger LoggerInterface) StructuredLogger {
+	return &printfLogger{logger: logger}
+}
+
+type printfLogger struct {
+	logger LoggerInterface
+}
+
+func (p *printfLogger) Enabled(level Level) bool {
+	return true
+}
+
+func (p *printfLogger) Log(level Level, msg string, fields ...Field) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, field := range fields {
+		fmt.Fprintf(&b, " %s=%v", field.Key, field.Value)
+	}
+	switch level {
+	case LevelDebug:
+		p.logger.Debugf("%s", b.String())
+	case LevelInfo:
+		p.logger.Infof("%s", b.String())
+	case LevelWarn:
+		p.logger.Warnf("%s", b.String())
+	default:
+		p.logger.Errorf("%s", b.String())
+	}
+}
+
+// WithLevel 过滤低于 level 级别的日志
+func WithLevel(logger StructuredLogger, level Level) StructuredLogger {
+	if f, ok := logger.(*levelFilter); ok {
+		logger = f.next
+	}
+	if g, ok := logger.(globalLogger); ok {
+		g.filtered = false
+		logger = g
+	}
+	return &levelFilter{next: logger, level: level}
+}
+
+type levelFilter struct {
+	next  StructuredLogger
+	level Level
+}
+
+func (f *levelFilter) Enabled(level Level) bool {
+	return level >= f.level && f.next.Enabled(level)
+}
+
+func (f *levelFilter) Log(level Level, msg string, fields ...Field) {
+	if level < f.level {
+		return
+	}
+	f.next.Log(level, msg, fields...)
+}
+
+// structuredLogger 通过 SetStructuredLogger 设置的全局结构化日志，为空时使用 Logger
+var structuredLogger atomic.Value
+
+// minLevel 全局日志级别，低于该级别的日志不输出
+var minLevel int32
+
+// SetStructuredLogger 设置全局结构化日志，设置后 Debugw 等方法不再输出到 Logger
+func SetStructuredLogger(logger StructuredLogger) {
+	structuredLogger.Store(&logger)
+}
+
+// SetLevel 设置全局日志级别，低于该级别的日志直接丢弃，默认输出全部级别
+func SetLevel(level Level) {
+	atomic.StoreInt32(&minLevel, int32(level))
+}
+
+// GetLevel 获取全局日志级别
+func GetLevel() Level {
+	return Level(atomic.LoadInt32(&minLevel))
+}
+
+// Enabled 判断全局日志是否输出 level 级别的日志
+func Enabled(level Level) bool {
+	return GetStructuredLogger().Enabled(level)
+}
+
+// GetStructuredLogger 获取按全局日志级别过滤的全局结构化日志
+func GetStructuredLogger() StructuredLogger {
+	return WithLevel(getRawStructuredLogger(), GetLevel())
+}
+
+func getRawStructuredLogger() StructuredLogger {
+	if l, ok := structuredLogger.Load().(*StructuredLogger); ok && *l != nil {
+		return *l
+	}
+	return NewStructuredLogger(Logger)
+}
+
+// Global 获取全局结构化日志，每次输出时读取当前的全局日志及全局日志级别，
+// 之后调用 SetStructuredLogger、SetLevel 同样生效；经 WithLevel 包装后不再按全局日志级别过滤
+func Global() StructuredLogger {
+	return globalLogger{filtered: true}
+}
+
+type globalLogger struct {
+	filtered bool
+}
+
+func (g globalLogger) current() StructuredLogger {
+	if g.filtered {
+		return GetStructuredLogger()
+	}
+	return getRawStructuredLogger()
+}
+
+func (g globalLogger) Enabled(level Level) bool {
+	return g.current().Enabled(level)
+}
+
+func (g globalLogger) Log(level Level, msg string, fields ...Field) {
+	g.current().Log(level, msg, fields...)
+}
+
+// Debugw 输出 debug 级别的结构化日志
+func Debugw(msg string, fields ...Field) {
+	GetStructuredLogger().Log(LevelDebug, msg, fields...)
+}
+
+// Infow 输出 info 级别的结构化日志
+func Infow(msg string, fields ...Field) {
+	GetStructuredLogger().Log(LevelInfo, msg, fields...)
+}
+
+// Warnw 输出 warn 级别的结构化日志
+func Warnw(msg string, fields ...Field) {
+	GetStructuredLogger().Log(LevelWarn, msg, fields...)
+}
+
+// Errorw 输出 error 级别的结构化日志
+func Errorw(msg string, fields ...Field) {
+	GetStructuredLogger().Log(LevelError, msg, fields...)
+}
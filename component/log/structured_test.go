@@ -0,0 +1,137 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+)
+
+type recordLogger struct {
+	DefaultLogger
+	logs []string
+}
+
+func (r *recordLogger) Debugf(format string, params ...interface{}) {
+	r.logs = append(r.logs, "debug:"+fmt.Sprintf(format, params...))
+}
+
+func (r *recordLogger) Warnf(format string, params ...interface{}) {
+	r.logs = append(r.logs, "warn:"+fmt.Sprintf(format, params...))
+}
+
+func (r *recordLogger) Errorf(format string, params ...interface{}) {
+	r.logs = append(r.logs, "error:"+fmt.Sprintf(format, params...))
+}
+
+type recordStructuredLogger struct {
+	msgs []string
+}
+
+func (r *recordStructuredLogger) Enabled(level Level) bool {
+	return true
+}
+
+func (r *recordStructuredLogger) Log(level Level, msg string, fields ...Field) {
+	r.msgs = append(r.msgs, level.String()+":"+msg)
+}
+
+func setTestLogger(t *testing.T, logger LoggerInterface) {
+	old := Logger
+	InitLogger(logger)
+	t.Cleanup(func() {
+		InitLogger(old)
+		SetLevel(LevelDebug)
+		SetStructuredLogger(nil)
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel(" WARNING ")
+	Assert(t, err, NilVal())
+	Assert(t, level, Equal(LevelWarn))
+
+	level, err = ParseLevel("error")
+	Assert(t, err, NilVal())
+	Assert(t, level.String(), Equal("error"))
+
+	_, err = ParseLevel("trace")
+	Assert(t, err, NotNilVal())
+	Assert(t, Level(10).String(), Equal("Level(10)"))
+}
+
+func TestStructuredLoggerAdapter(t *testing.T) {
+	r := &recordLogger{}
+	setTestLogger(t, r)
+
+	Debugw("get all server info", KV("url", "http://localhost:8080/"), KV("status", 200))
+	Errorw("100% fail")
+	Assert(t, r.logs, Equal([]string{
+		"debug:get all server info url=http://localhost:8080/ status=200",
+		"error:100% fail",
+	}))
+}
+
+func TestSetLevel(t *testing.T) {
+	r := &recordLogger{}
+	setTestLogger(t, r)
+	SetLevel(LevelWarn)
+
+	Debugf("debug")
+	Debugw("debug")
+	Warnf("warn")
+	Errorw("error")
+	Assert(t, r.logs, Equal([]string{"warn:warn", "error:error"}))
+	Assert(t, Enabled(LevelDebug), Equal(false))
+	Assert(t, Enabled(LevelWarn), Equal(true))
+}
+
+func TestSetStructuredLogger(t *testing.T) {
+	r := &recordLogger{}
+	setTestLogger(t, r)
+	s := &recordStructuredLogger{}
+	SetStructuredLogger(s)
+
+	Infow("info")
+	Warnw("warn")
+	Assert(t, s.msgs, Equal([]string{"info:info", "warn:warn"}))
+	Assert(t, len(r.logs), Equal(0))
+
+	filtered := WithLevel(WithLevel(s, LevelInfo), LevelError)
+	filtered.Log(LevelWarn, "filtered")
+	Assert(t, len(s.msgs), Equal(2))
+	Assert(t, filtered.(*levelFilter).next == StructuredLogger(s), Equal(true))
+}
+
+func TestGlobal(t *testing.T) {
+	r := &recordLogger{}
+	setTestLogger(t, r)
+	global := Global()
+	debug := WithLevel(global, LevelDebug)
+	SetLevel(LevelWarn)
+
+	global.Log(LevelDebug, "filtered")
+	debug.Log(LevelDebug, "debug")
+	Assert(t, r.logs, Equal([]string{"debug:debug"}))
+
+	s := &recordStructuredLogger{}
+	SetStructuredLogger(s)
+	global.Log(LevelError, "error")
+	Assert(t, s.msgs, Equal([]string{"error:error"}))
+	Assert(t, global.Enabled(LevelDebug), Equal(false))
+	Assert(t, debug.Enabled(LevelDebug), Equal(true))
+}
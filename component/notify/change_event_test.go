@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"sync"
 	"testing"
-	"time"
 
 	. "github.com/tevid/gohamcrest"
 
@@ -74,23 +73,17 @@ func buildNotifyResult(t *testing.T) {
 	server := runChangeConfigResponse()
 	defer server.Close()
 
-	time.Sleep(1 * time.Second)
-
 	newAppConfig := getTestAppConfig()
 	newAppConfig.IP = server.URL
 
-	syncApolloConfig := remote.CreateSyncApolloConfig()
-	apolloConfigs := syncApolloConfig.Sync(func() config.AppConfig {
-		return *newAppConfig
-	})
-	apolloConfigs = syncApolloConfig.Sync(func() config.AppConfig {
+	// configfiles 接口不返回 appId 与 releaseKey，使用 configs 接口同步
+	apolloConfig, err := remote.CreateAsyncApolloConfig().SyncWithNamespace(newAppConfig.NamespaceName, func() config.AppConfig {
 		return *newAppConfig
 	})
+	Assert(t, err, NilVal())
+	Assert(t, apolloConfig, NotNilVal())
 
-	Assert(t, apolloConfigs, NotNilVal())
-	Assert(t, len(apolloConfigs), Equal(1))
-
-	newAppConfig.GetCurrentApolloConfig().Set(newAppConfig.NamespaceName, &apolloConfigs[0].ApolloConnConfig)
+	newAppConfig.GetCurrentApolloConfig().Set(newAppConfig.NamespaceName, &apolloConfig.ApolloConnConfig)
 	config := newAppConfig.GetCurrentApolloConfig().Get()[newAppConfig.NamespaceName]
 
 	Assert(t, "100004458", Equal(config.AppID))
@@ -118,7 +111,14 @@ func TestListenChangeEvent(t *testing.T) {
 
 func TestRemoveChangeListener(t *testing.T) {
 	cache := storage.CreateNamespaceConfig("abc")
-	go buildNotifyResult(t)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		buildNotifyResult(t)
+	}()
+	// 等待 buildNotifyResult 结束，避免测试结束后断言失败导致 panic
+	defer wg.Wait()
 
 	listener := &CustomChangeListener{}
 	cache.AddChangeListener(listener)
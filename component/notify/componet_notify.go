@@ -15,70 +15,264 @@
 package notify
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/apolloconfig/agollo/v5/component"
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/component/remote"
+	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	"github.com/apolloconfig/agollo/v5/storage"
 )
 
-const (
-	longPollInterval = 2 * time.Second //2s
-)
-
 // ConfigComponent 配置组件
 type ConfigComponent struct {
 	appConfigFunc func() config.AppConfig
 	cache         *storage.Cache
+	extensions    *env.Extensions
 	stopCh        chan struct{}
 	stopOnce      sync.Once
 	stopMu        sync.Mutex
+	doneCh        chan struct{}
+	// updateMu 串行化长轮询与定期对账对缓存的更新，拉取配置时不持有
+	updateMu sync.Mutex
+	// fetchSeq 每次拉取配置前递增，appliedSeq 记录 namespace 最后一次更新所用拉取的序号，由 updateMu 保护
+	fetchSeq   atomic.Uint64
+	appliedSeq map[string]uint64
+	// wakeCh 通知长轮询立即开始下一次请求，pollCancel 取消正在进行的长轮询，均由 stopMu 保护
+	wakeCh     chan struct{}
+	pollCancel context.CancelFunc
 }
 
 func NewConfigComponent(appConfigFunc func() config.AppConfig, cache *storage.Cache) *ConfigComponent {
+	return NewConfigComponentWithExtensions(appConfigFunc, cache, nil)
+}
+
+// NewConfigComponentWithExtensions 创建使用客户端独占组件的配置组件，extensions 为 nil 时使用全局组件
+func NewConfigComponentWithExtensions(appConfigFunc func() config.AppConfig, cache *storage.Cache, extensions *env.Extensions) *ConfigComponent {
 	return &ConfigComponent{
 		appConfigFunc: appConfigFunc,
 		cache:         cache,
+		extensions:    extensions,
 		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
 	}
 }
 
 // Start 启动配置组件定时器
 func (c *ConfigComponent) Start() {
 	stopCh := c.ensureStopCh()
-	t2 := time.NewTimer(longPollInterval)
+	defer close(c.ensureDoneCh())
+	ctx, cancel := component.StopContext(stopCh)
+	defer cancel()
+	t2 := time.NewTimer(c.getLongPollInterval())
 	defer t2.Stop()
-	instance := remote.CreateAsyncApolloConfig()
-	log.Debug("ConfigComponent started")
+	instance := remote.CreateAsyncApolloConfigWithExtensions(c.extensions)
+
+	// 定期对账独立于长轮询运行，长轮询请求被代理挂起时也能修复丢失的变更通知
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.startReconcile(ctx, instance)
+	}()
+
+	wakeCh := c.ensureWakeCh()
+	appConfig := c.getAppConfig()
+	logger := appConfig.GetLogger()
+	logger.Log(log.LevelDebug, "ConfigComponent started")
 	//long poll for sync
 	for {
 		select {
 		case <-t2.C:
-			configs := instance.Sync(c.appConfigFunc)
-			for _, apolloConfig := range configs {
-				c.cache.UpdateApolloConfig(apolloConfig, c.appConfigFunc)
+			c.poll(ctx, instance)
+			t2.Reset(c.getLongPollInterval())
+		case <-wakeCh:
+			if !t2.Stop() {
+				select {
+				case <-t2.C:
+				default:
+				}
 			}
-			t2.Reset(longPollInterval)
+			c.poll(ctx, instance)
+			t2.Reset(c.getLongPollInterval())
 		case <-stopCh:
-			log.Debug("ConfigComponent stopped")
+			logger.Log(log.LevelDebug, "ConfigComponent stopped")
 			return
 		}
 	}
 }
 
+// poll 发起一次长轮询并更新有变化的 namespace，Wake 会取消正在进行的长轮询
+func (c *ConfigComponent) poll(ctx context.Context, instance remote.ApolloConfig) {
+	pollCtx, cancel := context.WithCancel(ctx)
+	c.stopMu.Lock()
+	c.pollCancel = cancel
+	c.stopMu.Unlock()
+	defer func() {
+		c.stopMu.Lock()
+		c.pollCancel = nil
+		c.stopMu.Unlock()
+		cancel()
+	}()
+
+	// 长轮询可能挂起较长时间，序号在通过 configs 接口拉取 namespace 时获取，
+	// 未拉取的配置（如从备份文件读取）使用发起长轮询时的序号
+	seq := c.fetchSeq.Add(1)
+	var configs []*config.ApolloConfig
+	fetchSeqs := make(map[string]uint64)
+	if observer, ok := instance.(remote.FetchObserver); ok {
+		configs = observer.SyncContextObserve(pollCtx, c.appConfigFunc, func(namespace string) {
+			fetchSeqs[namespace] = c.fetchSeq.Add(1)
+		})
+	} else {
+		configs = instance.SyncContext(pollCtx, c.appConfigFunc)
+	}
+	c.updateMu.Lock()
+	defer c.updateMu.Unlock()
+	appConfig := c.getAppConfig()
+	for _, apolloConfig := range configs {
+		// 长轮询期间被取消订阅的 namespace 不再更新
+		if !appConfig.ContainsNamespace(apolloConfig.NamespaceName) {
+			appConfig.GetNotificationsMap().RemoveNotify(apolloConfig.NamespaceName)
+			continue
+		}
+		if fetchSeq, ok := fetchSeqs[apolloConfig.NamespaceName]; ok {
+			c.applyIfNewer(fetchSeq, apolloConfig)
+			continue
+		}
+		c.applyIfNewer(seq, apolloConfig)
+	}
+}
+
+// applyIfNewer 拉取开始得比 namespace 上一次更新所用的拉取晚时才更新缓存，
+// 避免先发起、后返回的拉取覆盖较新的配置，调用方需持有 updateMu
+func (c *ConfigComponent) applyIfNewer(seq uint64, apolloConfig *config.ApolloConfig) bool {
+	if c.appliedSeq == nil {
+		c.appliedSeq = make(map[string]uint64)
+	}
+	if seq < c.appliedSeq[apolloConfig.NamespaceName] {
+		return false
+	}
+	c.appliedSeq[apolloConfig.NamespaceName] = seq
+	c.cache.UpdateApolloConfig(apolloConfig, c.appConfigFunc)
+	return true
+}
+
+// RemoveNamespace 停止长轮询 namespace 并移除其配置缓存，调用前需先从 AppConfig.NamespaceName 中移除该 namespace
+func (c *ConfigComponent) RemoveNamespace(namespace string) {
+	c.updateMu.Lock()
+	appConfig := c.getAppConfig()
+	if notificationsMap := appConfig.GetNotificationsMap(); notificationsMap != nil {
+		notificationsMap.RemoveNotify(namespace)
+	}
+	if currentApolloConfig := appConfig.GetCurrentApolloConfig(); currentApolloConfig != nil {
+		currentApolloConfig.Remove(namespace)
+	}
+	c.cache.RemoveNamespace(namespace)
+	delete(c.appliedSeq, namespace)
+	c.updateMu.Unlock()
+
+	c.Wake()
+}
+
+// Wake 取消正在进行的长轮询并立即发起新的长轮询，使订阅或取消订阅的 namespace 及时生效
+func (c *ConfigComponent) Wake() {
+	select {
+	case c.ensureWakeCh() <- struct{}{}:
+	default:
+	}
+	c.stopMu.Lock()
+	defer c.stopMu.Unlock()
+	if c.pollCancel != nil {
+		c.pollCancel()
+	}
+}
+
+func (c *ConfigComponent) getLongPollInterval() time.Duration {
+	appConfig := c.getAppConfig()
+	return appConfig.GetLongPollInterval()
+}
+
+func (c *ConfigComponent) getAppConfig() config.AppConfig {
+	if c.appConfigFunc == nil {
+		return config.AppConfig{}
+	}
+	return c.appConfigFunc()
+}
+
+// startReconcile 按 AppConfig.RefreshInterval 定期对账，ctx 结束或未开启定期对账时退出
+func (c *ConfigComponent) startReconcile(ctx context.Context, instance remote.ApolloConfig) {
+	appConfig := c.getAppConfig()
+	interval := appConfig.GetRefreshInterval()
+	if interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			c.reconcile(ctx, instance)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reconcile 携带本地的 releaseKey 通过 configs 接口拉取所有 namespace，配置未变化时服务端返回 304；
+// releaseKey 不一致时按正常流程更新缓存，存在差异的配置项通过 ChangeEvent 通知监听器
+func (c *ConfigComponent) reconcile(ctx context.Context, instance remote.ApolloConfig) {
+	appConfig := c.getAppConfig()
+	logger := appConfig.GetLogger()
+	config.SplitNamespaces(appConfig.NamespaceName, func(namespace string) {
+		if ctx.Err() != nil {
+			return
+		}
+		seq := c.fetchSeq.Add(1)
+		apolloConfig, err := instance.SyncWithNamespaceContext(ctx, namespace, c.appConfigFunc)
+		if err != nil || apolloConfig == nil {
+			return
+		}
+		c.updateMu.Lock()
+		defer c.updateMu.Unlock()
+		// 对账期间被取消订阅的 namespace 不再更新
+		if current := c.getAppConfig(); !current.ContainsNamespace(namespace) {
+			return
+		}
+		releaseKey := appConfig.GetCurrentApolloConfig().GetReleaseKey(namespace)
+		if apolloConfig.ReleaseKey == releaseKey {
+			return
+		}
+		if !c.applyIfNewer(seq, apolloConfig) {
+			return
+		}
+		// 首次同步使用的 configfiles 接口不返回 releaseKey，本地为空时不视为偏差
+		if releaseKey != "" {
+			logger.Log(log.LevelWarn, "reconcile found config drift",
+				log.KV("namespace", namespace),
+				log.KV("localReleaseKey", releaseKey),
+				log.KV("remoteReleaseKey", apolloConfig.ReleaseKey))
+		}
+	})
+}
+
 // Stop 停止配置组件定时器
 func (c *ConfigComponent) Stop() {
 	c.stopOnce.Do(func() {
-		c.stopMu.Lock()
-		defer c.stopMu.Unlock()
-		if c.stopCh != nil {
-			close(c.stopCh)
-		}
+		close(c.ensureStopCh())
 	})
 }
 
+// Done 配置组件定时器退出后关闭
+func (c *ConfigComponent) Done() <-chan struct{} {
+	return c.ensureDoneCh()
+}
+
 func (c *ConfigComponent) ensureStopCh() chan struct{} {
 	c.stopMu.Lock()
 	defer c.stopMu.Unlock()
@@ -87,3 +281,21 @@ func (c *ConfigComponent) ensureStopCh() chan struct{} {
 	}
 	return c.stopCh
 }
+
+func (c *ConfigComponent) ensureWakeCh() chan struct{} {
+	c.stopMu.Lock()
+	defer c.stopMu.Unlock()
+	if c.wakeCh == nil {
+		c.wakeCh = make(chan struct{}, 1)
+	}
+	return c.wakeCh
+}
+
+func (c *ConfigComponent) ensureDoneCh() chan struct{} {
+	c.stopMu.Lock()
+	defer c.stopMu.Unlock()
+	if c.doneCh == nil {
+		c.doneCh = make(chan struct{})
+	}
+	return c.doneCh
+}
@@ -15,12 +15,18 @@
 package notify
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	. "github.com/tevid/gohamcrest"
 
 	"github.com/apolloconfig/agollo/v5/cluster/roundrobin"
+	"github.com/apolloconfig/agollo/v5/component/remote"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	jsonConfig "github.com/apolloconfig/agollo/v5/env/config/json"
@@ -134,3 +140,219 @@ func TestConfigComponent_Stop(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigComponent_Done(t *testing.T) {
+	c := &ConfigComponent{}
+	c.Stop()
+	go c.Start()
+
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("ConfigComponent not done after Stop")
+	}
+}
+
+func TestGetLongPollInterval(t *testing.T) {
+	c := &ConfigComponent{}
+	Assert(t, c.getLongPollInterval(), Equal(2*time.Second))
+
+	c.appConfigFunc = func() config.AppConfig {
+		return config.AppConfig{LongPollInterval: config.Duration(time.Second)}
+	}
+	Assert(t, c.getLongPollInterval(), Equal(time.Second))
+}
+
+func TestConfigComponentReconcile(t *testing.T) {
+	server := runChangeConfigResponse()
+	defer server.Close()
+
+	appConfig := getTestAppConfig()
+	appConfig.IP = server.URL
+	appConfig.IsBackupConfig = false
+	appConfig.LongPollInterval = config.Duration(time.Hour)
+	appConfig.RefreshInterval = config.Duration(100 * time.Millisecond)
+	appConfigFunc := func() config.AppConfig {
+		return *appConfig
+	}
+	cache := storage.CreateNamespaceConfig(appConfig.NamespaceName)
+	c := NewConfigComponent(appConfigFunc, cache)
+	go c.Start()
+	defer c.Stop()
+
+	deadline := time.Now().Add(3 * time.Second)
+	namespaceConfig := cache.GetConfig("application")
+	for !namespaceConfig.GetIsInit() || namespaceConfig.GetValue("key1") != "value1" {
+		if time.Now().After(deadline) {
+			t.Fatal("namespace not reconciled")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+type reconcileChangeListener struct {
+	events chan *storage.ChangeEvent
+}
+
+func (l *reconcileChangeListener) OnChange(event *storage.ChangeEvent) {
+	l.events <- event
+}
+
+func (l *reconcileChangeListener) OnNewestChange(event *storage.FullChangeEvent) {
+}
+
+func TestReconcile(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Query().Get("releaseKey") == "20170430092936-dee2d58e74515ff3" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(configChangeResponseStr))
+	}))
+	defer server.Close()
+
+	appConfig := getTestAppConfig()
+	appConfig.IP = server.URL
+	appConfig.IsBackupConfig = false
+	appConfigFunc := func() config.AppConfig {
+		return *appConfig
+	}
+	cache := storage.CreateNamespaceConfig(appConfig.NamespaceName)
+	apolloConfig := &config.ApolloConfig{}
+	apolloConfig.NamespaceName = "application"
+	apolloConfig.ReleaseKey = "old"
+	apolloConfig.Configurations = map[string]interface{}{"key1": "old", "key2": "value2"}
+	cache.UpdateApolloConfig(apolloConfig, appConfigFunc)
+
+	listener := &reconcileChangeListener{events: make(chan *storage.ChangeEvent, 1)}
+	cache.AddChangeListener(listener)
+	c := NewConfigComponent(appConfigFunc, cache)
+	instance := remote.CreateAsyncApolloConfig()
+
+	c.reconcile(context.Background(), instance)
+	select {
+	case event := <-listener.events:
+		Assert(t, event.Namespace, Equal("application"))
+		Assert(t, event.Changes["key1"].NewValue, Equal("value1"))
+		Assert(t, event.Changes["string"].ChangeType, Equal(storage.ADDED))
+	case <-time.After(time.Second):
+		t.Fatal("drift not reported")
+	}
+	Assert(t, appConfig.GetCurrentApolloConfig().GetReleaseKey("application"), Equal("20170430092936-dee2d58e74515ff3"))
+
+	c.reconcile(context.Background(), instance)
+	Assert(t, atomic.LoadInt32(&requests), Equal(int32(2)))
+	select {
+	case <-listener.events:
+		t.Fatal("unexpected change event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConfigComponentWake(t *testing.T) {
+	var notifyRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/notifications/v2" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&notifyRequests, 1)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	appConfig := getTestAppConfig()
+	appConfig.IP = server.URL
+	appConfig.IsBackupConfig = false
+	appConfig.LongPollInterval = config.Duration(10 * time.Millisecond)
+	appConfig.RefreshInterval = config.Duration(time.Hour)
+	appConfigFunc := func() config.AppConfig {
+		return *appConfig
+	}
+	c := NewConfigComponent(appConfigFunc, storage.CreateNamespaceConfig(appConfig.NamespaceName))
+	go c.Start()
+	defer c.Stop()
+
+	waitRequests := func(n int32) {
+		deadline := time.Now().Add(time.Second)
+		for atomic.LoadInt32(&notifyRequests) < n {
+			if time.Now().After(deadline) {
+				t.Fatalf("expect %d long poll requests, got %d", n, atomic.LoadInt32(&notifyRequests))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitRequests(1)
+	c.Wake()
+	waitRequests(2)
+}
+
+func TestApplyIfNewer(t *testing.T) {
+	appConfig := getTestAppConfig()
+	appConfig.IsBackupConfig = false
+	appConfigFunc := func() config.AppConfig {
+		return *appConfig
+	}
+	cache := storage.CreateNamespaceConfig(appConfig.NamespaceName)
+	c := NewConfigComponent(appConfigFunc, cache)
+	newApolloConfig := func(releaseKey string) *config.ApolloConfig {
+		apolloConfig := &config.ApolloConfig{}
+		apolloConfig.NamespaceName = "application"
+		apolloConfig.ReleaseKey = releaseKey
+		apolloConfig.Configurations = map[string]interface{}{"key1": releaseKey}
+		return apolloConfig
+	}
+
+	Assert(t, c.applyIfNewer(2, newApolloConfig("newer")), Equal(true))
+	// 先发起的拉取后返回时不覆盖较新的配置
+	Assert(t, c.applyIfNewer(1, newApolloConfig("older")), Equal(false))
+	Assert(t, cache.GetConfig("application").GetValue("key1"), Equal("newer"))
+	Assert(t, appConfig.GetCurrentApolloConfig().GetReleaseKey("application"), Equal("newer"))
+	Assert(t, c.applyIfNewer(3, newApolloConfig("latest")), Equal(true))
+	Assert(t, cache.GetConfig("application").GetValue("key1"), Equal("latest"))
+}
+
+// holdApolloConfig 模拟长轮询挂起期间对账更新了配置，之后返回变更通知并拉取较新的配置
+type holdApolloConfig struct {
+	remote.ApolloConfig
+	duringHold func()
+	result     *config.ApolloConfig
+}
+
+func (h *holdApolloConfig) SyncContextObserve(ctx context.Context, appConfigFunc func() config.AppConfig, onFetch func(namespace string)) []*config.ApolloConfig {
+	h.duringHold()
+	onFetch(h.result.NamespaceName)
+	return []*config.ApolloConfig{h.result}
+}
+
+func TestPollOrdersByFetchStart(t *testing.T) {
+	appConfig := getTestAppConfig()
+	appConfig.IsBackupConfig = false
+	appConfigFunc := func() config.AppConfig {
+		return *appConfig
+	}
+	cache := storage.CreateNamespaceConfig(appConfig.NamespaceName)
+	c := NewConfigComponent(appConfigFunc, cache)
+	newApolloConfig := func(releaseKey string) *config.ApolloConfig {
+		apolloConfig := &config.ApolloConfig{}
+		apolloConfig.NamespaceName = "application"
+		apolloConfig.ReleaseKey = releaseKey
+		apolloConfig.Configurations = map[string]interface{}{"key1": releaseKey}
+		return apolloConfig
+	}
+
+	instance := &holdApolloConfig{
+		duringHold: func() {
+			seq := c.fetchSeq.Add(1)
+			c.updateMu.Lock()
+			defer c.updateMu.Unlock()
+			Assert(t, c.applyIfNewer(seq, newApolloConfig("reconcile")), Equal(true))
+		},
+		result: newApolloConfig("poll"),
+	}
+	c.poll(context.Background(), instance)
+	Assert(t, cache.GetConfig("application").GetValue("key1"), Equal("poll"))
+}
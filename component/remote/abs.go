@@ -15,20 +15,30 @@
 package remote
 
 import (
+	"context"
 	"time"
 
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/extension"
 	"github.com/apolloconfig/agollo/v5/protocol/http"
 )
 
 // AbsApolloConfig 抽象 apollo 配置
 type AbsApolloConfig struct {
 	remoteApollo ApolloConfig
+	// extensions 客户端独占的组件，为空时使用全局组件
+	extensions *env.Extensions
 }
 
+// SyncWithNamespace 通过 namespace 同步 apollo 配置
 func (a *AbsApolloConfig) SyncWithNamespace(namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
+	return a.SyncWithNamespaceContext(context.Background(), namespace, appConfigFunc)
+}
+
+// SyncWithNamespaceContext 通过 namespace 同步 apollo 配置，ctx 结束后停止请求
+func (a *AbsApolloConfig) SyncWithNamespaceContext(ctx context.Context, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error) {
 	if appConfigFunc == nil {
 		panic("can not find apollo config!please confirm!")
 	}
@@ -36,27 +46,45 @@ func (a *AbsApolloConfig) SyncWithNamespace(namespace string, appConfigFunc func
 	urlSuffix := a.remoteApollo.GetSyncURI(appConfig, namespace)
 
 	c := &env.ConnectConfig{
-		URI:     urlSuffix,
-		AppID:   appConfig.AppID,
-		Secret:  appConfig.Secret,
-		Timeout: notifyConnectTimeout,
-		IsRetry: true,
+		URI:           urlSuffix,
+		AppID:         appConfig.AppID,
+		Secret:        appConfig.Secret,
+		Timeout:       appConfig.GetNotifyConnectTimeout(),
+		IsRetry:       true,
+		Transport:     appConfig.Transport,
+		HTTPTransport: appConfig.HTTPTransport,
+		TLS:           appConfig.TLS,
+		Logger:        appConfig.GetLogger(),
+		Extensions:    a.extensions,
 	}
 	if appConfig.SyncServerTimeout > 0 {
 		c.Timeout = time.Duration(appConfig.SyncServerTimeout) * time.Second
 	}
 
 	callback := a.remoteApollo.CallBack(namespace)
-	apolloConfig, err := http.RequestRecovery(appConfig, c, &callback)
+	callback.AppConfigFunc = appConfigFunc
+	apolloConfig, err := http.RequestRecoveryContext(ctx, appConfig, c, &callback)
 	if err != nil {
-		log.Errorf("request %s fail, error:%v", urlSuffix, err)
+		c.Logger.Log(log.LevelError, "sync namespace fail", log.KV("namespace", namespace), log.KV("url", urlSuffix), log.KV("error", err))
+		if ctx.Err() == nil {
+			extension.GetMetrics().IncSyncFailure(namespace)
+		}
 		return nil, err
 	}
 
 	if apolloConfig == nil {
-		log.Debug("apolloConfig is nil")
+		c.Logger.Log(log.LevelDebug, "apolloConfig is nil", log.KV("namespace", namespace))
 		return nil, nil
 	}
 
 	return apolloConfig.(*config.ApolloConfig), nil
 }
+
+// getCallBackLogger 获取回调使用的日志，未设置 CallBack.AppConfigFunc 时使用全局日志
+func getCallBackLogger(callback http.CallBack) log.StructuredLogger {
+	if callback.AppConfigFunc == nil {
+		return log.GetStructuredLogger()
+	}
+	appConfig := callback.AppConfigFunc()
+	return appConfig.GetLogger()
+}
@@ -15,11 +15,11 @@
 package remote
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"path"
-	"time"
 
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/constant"
@@ -27,20 +27,25 @@ import (
 	"github.com/apolloconfig/agollo/v5/env/config"
 	"github.com/apolloconfig/agollo/v5/extension"
 	"github.com/apolloconfig/agollo/v5/protocol/http"
+	"github.com/apolloconfig/agollo/v5/tracing"
 	"github.com/apolloconfig/agollo/v5/utils"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
 )
 
 const (
-	//notify timeout
-	notifyConnectTimeout = 10 * time.Minute //10m
-
 	defaultContentKey = "content"
 )
 
 // CreateAsyncApolloConfig 创建异步 apollo 配置
 func CreateAsyncApolloConfig() ApolloConfig {
+	return CreateAsyncApolloConfigWithExtensions(nil)
+}
+
+// CreateAsyncApolloConfigWithExtensions 创建使用客户端独占组件的异步 apollo 配置，extensions 为 nil 时使用全局组件
+func CreateAsyncApolloConfigWithExtensions(extensions *env.Extensions) ApolloConfig {
 	a := &asyncApolloConfig{}
 	a.remoteApollo = a
+	a.extensions = extensions
 	return a
 }
 
@@ -66,12 +71,25 @@ func (*asyncApolloConfig) GetSyncURI(config config.AppConfig, namespaceName stri
 }
 
 func (a *asyncApolloConfig) Sync(appConfigFunc func() config.AppConfig) []*config.ApolloConfig {
+	return a.SyncContext(context.Background(), appConfigFunc)
+}
+
+func (a *asyncApolloConfig) SyncContext(ctx context.Context, appConfigFunc func() config.AppConfig) []*config.ApolloConfig {
+	return a.SyncContextObserve(ctx, appConfigFunc, nil)
+}
+
+// SyncContextObserve 实现 FetchObserver，长轮询返回变更通知后，拉取每个 namespace 前调用 onFetch
+func (a *asyncApolloConfig) SyncContextObserve(ctx context.Context, appConfigFunc func() config.AppConfig, onFetch func(namespace string)) []*config.ApolloConfig {
 	appConfig := appConfigFunc()
-	remoteConfigs, err := a.notifyRemoteConfig(appConfigFunc, utils.Empty)
+	remoteConfigs, err := a.notifyRemoteConfig(ctx, appConfigFunc, utils.Empty)
+	// 主动停止时不回退到备份文件
+	if ctx.Err() != nil {
+		return nil
+	}
 
 	var apolloConfigs []*config.ApolloConfig
 	if err != nil {
-		apolloConfigs = loadBackupConfig(appConfig.NamespaceName, appConfig)
+		apolloConfigs = loadBackupConfig(ctx, appConfig.NamespaceName, appConfig, a.extensions)
 	}
 
 	if len(remoteConfigs) == 0 || len(apolloConfigs) > 0 {
@@ -79,7 +97,10 @@ func (a *asyncApolloConfig) Sync(appConfigFunc func() config.AppConfig) []*confi
 	}
 	//只是拉去有变化的配置, 并更新拉取成功的namespace的notify ID
 	for _, notifyConfig := range remoteConfigs {
-		apolloConfig, err := a.SyncWithNamespace(notifyConfig.NamespaceName, appConfigFunc)
+		if onFetch != nil {
+			onFetch(notifyConfig.NamespaceName)
+		}
+		apolloConfig, err := a.SyncWithNamespaceContext(ctx, notifyConfig.NamespaceName, appConfigFunc)
 		// Update notificationID if we got a successful response (including 304)
 		if err == nil {
 			appConfig.GetNotificationsMap().UpdateNotify(notifyConfig.NamespaceName, notifyConfig.NotificationID)
@@ -91,15 +112,17 @@ func (a *asyncApolloConfig) Sync(appConfigFunc func() config.AppConfig) []*confi
 	return apolloConfigs
 }
 
-func (*asyncApolloConfig) CallBack(namespace string) http.CallBack {
+func (a *asyncApolloConfig) CallBack(namespace string) http.CallBack {
 	return http.CallBack{
-		SuccessCallBack:   createApolloConfigWithJSON,
+		SuccessCallBack: func(responseBody []byte, callback http.CallBack) (interface{}, error) {
+			return createApolloConfigWithJSON(responseBody, callback, a.extensions)
+		},
 		NotModifyCallBack: touchApolloConfigCache,
 		Namespace:         namespace,
 	}
 }
 
-func (a *asyncApolloConfig) notifyRemoteConfig(appConfigFunc func() config.AppConfig, namespace string) ([]*config.Notification, error) {
+func (a *asyncApolloConfig) notifyRemoteConfig(ctx context.Context, appConfigFunc func() config.AppConfig, namespace string) ([]*config.Notification, error) {
 	if appConfigFunc == nil {
 		panic("can not find apollo config!please confirm!")
 	}
@@ -108,14 +131,24 @@ func (a *asyncApolloConfig) notifyRemoteConfig(appConfigFunc func() config.AppCo
 	urlSuffix := a.GetNotifyURLSuffix(notificationsMap.GetNotifies(namespace), appConfig)
 
 	connectConfig := &env.ConnectConfig{
-		URI:    urlSuffix,
-		AppID:  appConfig.AppID,
-		Secret: appConfig.Secret,
+		URI:           urlSuffix,
+		AppID:         appConfig.AppID,
+		Secret:        appConfig.Secret,
+		Transport:     appConfig.Transport,
+		HTTPTransport: appConfig.HTTPTransport,
+		TLS:           appConfig.TLS,
+		Logger:        appConfig.GetLogger(),
+		Extensions:    a.extensions,
+		IsLongPoll:    true,
 	}
-	connectConfig.Timeout = notifyConnectTimeout
-	notifies, err := http.RequestRecovery(appConfig, connectConfig, &http.CallBack{
+	connectConfig.Timeout = appConfig.GetNotifyConnectTimeout()
+	notifies, err := http.RequestRecoveryContext(ctx, appConfig, connectConfig, &http.CallBack{
 		SuccessCallBack: func(responseBody []byte, callback http.CallBack) (interface{}, error) {
-			return toApolloConfig(responseBody)
+			notifies, err := toApolloConfig(responseBody)
+			if err != nil {
+				connectConfig.Logger.Log(log.LevelError, "unmarshal notifications fail", log.KV("error", err))
+			}
+			return notifies, err
 		},
 		NotModifyCallBack: touchApolloConfigCache,
 		Namespace:         namespace,
@@ -138,39 +171,45 @@ func toApolloConfig(resBody []byte) ([]*config.Notification, error) {
 	err := json.Unmarshal(resBody, &remoteConfig)
 
 	if err != nil {
-		log.Errorf("Unmarshal Msg Fail, error: %v", err)
 		return nil, err
 	}
 	return remoteConfig, nil
 }
 
-func loadBackupConfig(namespace string, appConfig config.AppConfig) []*config.ApolloConfig {
+func loadBackupConfig(ctx context.Context, namespace string, appConfig config.AppConfig, extensions *env.Extensions) []*config.ApolloConfig {
 	apolloConfigs := make([]*config.ApolloConfig, 0)
 	config.SplitNamespaces(namespace, func(namespace string) {
-		c, err := extension.GetFileHandler().LoadConfigFile(appConfig.BackupConfigPath, appConfig.AppID, namespace)
+		_, span := extension.GetTracer().Start(ctx, tracing.SpanLoadBackupConfig,
+			tracing.String(tracing.AppID, appConfig.AppID),
+			tracing.String(tracing.Cluster, appConfig.Cluster),
+			tracing.String(tracing.Namespace, namespace))
+		c, err := extensions.GetFileHandler().LoadConfigFile(appConfig.BackupConfigPath, appConfig.AppID, namespace)
+		if c != nil {
+			span.SetAttributes(tracing.String(tracing.ReleaseKey, c.ReleaseKey))
+		}
+		span.End(err)
 		if err != nil {
-			log.Errorf("LoadConfigFile error, error: %v", err)
+			appConfig.GetLogger().Log(log.LevelError, "load backup config fail", log.KV("namespace", namespace), log.KV("error", err))
 			return
 		}
 		if c == nil {
 			return
 		}
+		extension.GetMetrics().IncBackupFallback(namespace)
 		apolloConfigs = append(apolloConfigs, c)
 	})
 	return apolloConfigs
 }
 
-func createApolloConfigWithJSON(b []byte, callback http.CallBack) (o interface{}, err error) {
+func createApolloConfigWithJSON(b []byte, callback http.CallBack, extensions *env.Extensions) (o interface{}, err error) {
 	apolloConfig := &config.ApolloConfig{}
 	err = json.Unmarshal(b, apolloConfig)
 	if utils.IsNotNil(err) {
 		return nil, err
 	}
 
-	parser := extension.GetFormatParser(constant.ConfigFileFormat(path.Ext(apolloConfig.NamespaceName)))
-	if parser == nil {
-		parser = extension.GetFormatParser(constant.DEFAULT)
-	}
+	format := constant.ConfigFileFormat(path.Ext(apolloConfig.NamespaceName))
+	parser := getFormatParser(extensions, format)
 
 	if parser == nil {
 		return apolloConfig, nil
@@ -178,11 +217,15 @@ func createApolloConfigWithJSON(b []byte, callback http.CallBack) (o interface{}
 
 	content, ok := apolloConfig.Configurations[defaultContentKey]
 	if !ok {
+		// properties 格式返回的已是 key-value，只有 content 中的原始文本需要解析
+		if format == constant.Properties {
+			return apolloConfig, nil
+		}
 		content = string(b)
 	}
 	m, err := parser.Parse(content)
 	if err != nil {
-		log.Debugf("GetContent fail! error: %v", err)
+		getCallBackLogger(callback).Log(log.LevelDebug, "parse content fail", log.KV("namespace", apolloConfig.NamespaceName), log.KV("error", err))
 	}
 
 	if len(m) > 0 {
@@ -190,3 +233,12 @@ func createApolloConfigWithJSON(b []byte, callback http.CallBack) (o interface{}
 	}
 	return apolloConfig, nil
 }
+
+// getFormatParser 获取 namespace 内容解析器，不存在时使用默认解析器
+func getFormatParser(extensions *env.Extensions, format constant.ConfigFileFormat) parse.ContentParser {
+	parser := extensions.GetFormatParser(format)
+	if parser == nil {
+		parser = extensions.GetFormatParser(constant.DEFAULT)
+	}
+	return parser
+}
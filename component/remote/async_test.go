@@ -15,6 +15,7 @@
 package remote
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -261,7 +262,7 @@ func TestGetRemoteConfig(t *testing.T) {
 	var err error
 	appConfig := initNotifications()
 	appConfig.IP = server.URL
-	remoteConfigs, err = asyncApollo.notifyRemoteConfig(func() config.AppConfig {
+	remoteConfigs, err = asyncApollo.notifyRemoteConfig(context.Background(), func() config.AppConfig {
 		return *appConfig
 	}, EMPTY)
 
@@ -308,7 +309,7 @@ func testErrorGetRemoteConfig(t *testing.T, status int, errContained string) {
 	var remoteConfigs []*config.Notification
 	var err error
 
-	remoteConfigs, err = asyncApollo.notifyRemoteConfig(func() config.AppConfig {
+	remoteConfigs, err = asyncApollo.notifyRemoteConfig(context.Background(), func() config.AppConfig {
 		return *appConfig
 	}, EMPTY)
 
@@ -332,7 +333,7 @@ func TestCreateApolloConfigWithJson(t *testing.T) {
   },
   "releaseKey": "20170430092936-dee2d58e74515ff3"
 }`
-	o, err := createApolloConfigWithJSON([]byte(jsonStr), http2.CallBack{})
+	o, err := createApolloConfigWithJSON([]byte(jsonStr), http2.CallBack{}, nil)
 	c := o.(*config.ApolloConfig)
 
 	Assert(t, err, NilVal())
@@ -350,7 +351,7 @@ func TestCreateApolloConfigWithJson(t *testing.T) {
 func TestCreateApolloConfigWithJsonError(t *testing.T) {
 	jsonStr := `jklasdjflasjdfa`
 
-	config, err := createApolloConfigWithJSON([]byte(jsonStr), http2.CallBack{})
+	config, err := createApolloConfigWithJSON([]byte(jsonStr), http2.CallBack{}, nil)
 
 	Assert(t, err, NotNilVal())
 	Assert(t, config, NilVal())
@@ -15,6 +15,8 @@
 package remote
 
 import (
+	"context"
+
 	"github.com/apolloconfig/agollo/v5/env/config"
 	"github.com/apolloconfig/agollo/v5/protocol/http"
 )
@@ -27,8 +29,18 @@ type ApolloConfig interface {
 	GetSyncURI(config config.AppConfig, namespaceName string) string
 	// Sync 同步获取 Apollo 配置
 	Sync(appConfigFunc func() config.AppConfig) []*config.ApolloConfig
+	// SyncContext 同步获取 Apollo 配置，ctx 结束后停止请求
+	SyncContext(ctx context.Context, appConfigFunc func() config.AppConfig) []*config.ApolloConfig
 	// CallBack 根据 namespace 获取 callback 方法
 	CallBack(namespace string) http.CallBack
 	// SyncWithNamespace 通过 namespace 同步 apollo 配置
 	SyncWithNamespace(namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error)
+	// SyncWithNamespaceContext 通过 namespace 同步 apollo 配置，ctx 结束后停止请求
+	SyncWithNamespaceContext(ctx context.Context, namespace string, appConfigFunc func() config.AppConfig) (*config.ApolloConfig, error)
+}
+
+// FetchObserver ApolloConfig 可以实现的可选接口，与 SyncContext 相同，
+// 并在通过 configs 接口拉取每个 namespace 前调用 onFetch，用于按拉取开始的先后判断配置的新旧
+type FetchObserver interface {
+	SyncContextObserve(ctx context.Context, appConfigFunc func() config.AppConfig, onFetch func(namespace string)) []*config.ApolloConfig
 }
@@ -15,6 +15,7 @@
 package remote
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -22,16 +23,22 @@ import (
 
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/constant"
+	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
-	"github.com/apolloconfig/agollo/v5/extension"
 	"github.com/apolloconfig/agollo/v5/protocol/http"
 	"github.com/apolloconfig/agollo/v5/utils"
 )
 
 // CreateSyncApolloConfig 创建同步获取 Apollo 配置
 func CreateSyncApolloConfig() ApolloConfig {
+	return CreateSyncApolloConfigWithExtensions(nil)
+}
+
+// CreateSyncApolloConfigWithExtensions 创建使用客户端独占组件的同步获取 Apollo 配置，extensions 为 nil 时使用全局组件
+func CreateSyncApolloConfigWithExtensions(extensions *env.Extensions) ApolloConfig {
 	a := &syncApolloConfig{}
 	a.remoteApollo = a
+	a.extensions = extensions
 	return a
 }
 
@@ -52,15 +59,17 @@ func (*syncApolloConfig) GetSyncURI(config config.AppConfig, namespaceName strin
 		url.QueryEscape(config.Label))
 }
 
-func (*syncApolloConfig) CallBack(namespace string) http.CallBack {
+func (a *syncApolloConfig) CallBack(namespace string) http.CallBack {
 	return http.CallBack{
-		SuccessCallBack:   processJSONFiles,
+		SuccessCallBack: func(responseBody []byte, callback http.CallBack) (interface{}, error) {
+			return processJSONFiles(responseBody, callback, a.extensions)
+		},
 		NotModifyCallBack: touchApolloConfigCache,
 		Namespace:         namespace,
 	}
 }
 
-func processJSONFiles(b []byte, callback http.CallBack) (o interface{}, err error) {
+func processJSONFiles(b []byte, callback http.CallBack, extensions *env.Extensions) (o interface{}, err error) {
 	apolloConfig := &config.ApolloConfig{}
 	apolloConfig.NamespaceName = callback.Namespace
 
@@ -72,10 +81,8 @@ func processJSONFiles(b []byte, callback http.CallBack) (o interface{}, err erro
 		return nil, err
 	}
 
-	parser := extension.GetFormatParser(constant.ConfigFileFormat(path.Ext(apolloConfig.NamespaceName)))
-	if parser == nil {
-		parser = extension.GetFormatParser(constant.DEFAULT)
-	}
+	format := constant.ConfigFileFormat(path.Ext(apolloConfig.NamespaceName))
+	parser := getFormatParser(extensions, format)
 
 	if parser == nil {
 		return apolloConfig, nil
@@ -83,11 +90,15 @@ func processJSONFiles(b []byte, callback http.CallBack) (o interface{}, err erro
 
 	content, ok := configurations[defaultContentKey]
 	if !ok {
+		// properties 格式返回的已是 key-value，只有 content 中的原始文本需要解析
+		if format == constant.Properties {
+			return apolloConfig, nil
+		}
 		content = string(b)
 	}
 	m, err := parser.Parse(content)
 	if err != nil {
-		log.Debugf("GetContent fail! error: %v", err)
+		getCallBackLogger(callback).Log(log.LevelDebug, "parse content fail", log.KV("namespace", apolloConfig.NamespaceName), log.KV("error", err))
 	}
 
 	if len(m) > 0 {
@@ -97,15 +108,19 @@ func processJSONFiles(b []byte, callback http.CallBack) (o interface{}, err erro
 }
 
 func (a *syncApolloConfig) Sync(appConfigFunc func() config.AppConfig) []*config.ApolloConfig {
+	return a.SyncContext(context.Background(), appConfigFunc)
+}
+
+func (a *syncApolloConfig) SyncContext(ctx context.Context, appConfigFunc func() config.AppConfig) []*config.ApolloConfig {
 	appConfig := appConfigFunc()
 	configs := make([]*config.ApolloConfig, 0, 8)
 	config.SplitNamespaces(appConfig.NamespaceName, func(namespace string) {
-		apolloConfig, _ := a.SyncWithNamespace(namespace, appConfigFunc)
+		apolloConfig, _ := a.SyncWithNamespaceContext(ctx, namespace, appConfigFunc)
 		if apolloConfig != nil {
 			configs = append(configs, apolloConfig)
 			return
 		}
-		configs = append(configs, loadBackupConfig(namespace, appConfig)...)
+		configs = append(configs, loadBackupConfig(ctx, namespace, appConfig, a.extensions)...)
 	})
 	return configs
 }
@@ -28,8 +28,12 @@ import (
 	"github.com/apolloconfig/agollo/v5/env/config"
 	"github.com/apolloconfig/agollo/v5/env/server"
 	"github.com/apolloconfig/agollo/v5/extension"
+	http2 "github.com/apolloconfig/agollo/v5/protocol/http"
+	jsonParser "github.com/apolloconfig/agollo/v5/utils/parse/json"
 	"github.com/apolloconfig/agollo/v5/utils/parse/normal"
 	"github.com/apolloconfig/agollo/v5/utils/parse/properties"
+	"github.com/apolloconfig/agollo/v5/utils/parse/toml"
+	xmlParser "github.com/apolloconfig/agollo/v5/utils/parse/xml"
 	"github.com/apolloconfig/agollo/v5/utils/parse/yaml"
 	"github.com/apolloconfig/agollo/v5/utils/parse/yml"
 )
@@ -49,6 +53,9 @@ func init() {
 	extension.AddFormatParser(constant.Properties, &properties.Parser{})
 	extension.AddFormatParser(constant.YML, &yml.Parser{})
 	extension.AddFormatParser(constant.YAML, &yaml.Parser{})
+	extension.AddFormatParser(constant.TOML, &toml.Parser{})
+	extension.AddFormatParser(constant.JSON, &jsonParser.Parser{})
+	extension.AddFormatParser(constant.XML, &xmlParser.Parser{})
 }
 
 // Normal response
@@ -181,3 +188,34 @@ func TestClientLabelConfigService(t *testing.T) {
 	Assert(t, "gray_value1", Equal(apolloConfig.Configurations["key1"]))
 	Assert(t, "gray_value2", Equal(apolloConfig.Configurations["key2"]))
 }
+
+func TestProcessJSONFilesWithJSONNamespace(t *testing.T) {
+	o, err := processJSONFiles([]byte(`{"content":"{\"db\":{\"primary\":{\"host\":\"127.0.0.1\"}}}"}`), http2.CallBack{
+		Namespace: "db.json",
+	}, nil)
+	Assert(t, err, NilVal())
+
+	apolloConfig := o.(*config.ApolloConfig)
+	Assert(t, apolloConfig.NamespaceName, Equal("db.json"))
+	Assert(t, apolloConfig.Configurations["db.primary.host"], Equal("127.0.0.1"))
+}
+
+func TestProcessJSONFilesWithPropertiesNamespace(t *testing.T) {
+	o, err := processJSONFiles([]byte(`{"key1":"value1","key2":"value2"}`), http2.CallBack{
+		Namespace: "app.properties",
+	}, nil)
+	Assert(t, err, NilVal())
+
+	apolloConfig := o.(*config.ApolloConfig)
+	Assert(t, len(apolloConfig.Configurations), Equal(2))
+	Assert(t, apolloConfig.Configurations["key1"], Equal("value1"))
+
+	o, err = processJSONFiles([]byte(`{"content":"key1=value1\nkey2:value2"}`), http2.CallBack{
+		Namespace: "app.properties",
+	}, nil)
+	Assert(t, err, NilVal())
+
+	apolloConfig = o.(*config.ApolloConfig)
+	Assert(t, len(apolloConfig.Configurations), Equal(2))
+	Assert(t, apolloConfig.Configurations["key2"], Equal("value2"))
+}
@@ -15,7 +15,10 @@
 package serverlist
 
 import (
+	"context"
 	"encoding/json"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,15 +26,9 @@ import (
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
-	"github.com/apolloconfig/agollo/v5/env/server"
 	"github.com/apolloconfig/agollo/v5/protocol/http"
 )
 
-const (
-	//refresh ip list
-	refreshIPListInterval = 20 * time.Minute //20m
-)
-
 func init() {
 
 }
@@ -43,51 +40,77 @@ func InitSyncServerIPList(appConfig func() config.AppConfig) {
 }
 
 func NewSyncServerIPListComponent(appConfig func() config.AppConfig) *SyncServerIPListComponent {
+	return NewSyncServerIPListComponentWithExtensions(appConfig, nil)
+}
+
+// NewSyncServerIPListComponentWithExtensions 创建使用客户端独占组件的同步服务器列表组件，extensions 为 nil 时使用全局组件
+func NewSyncServerIPListComponentWithExtensions(appConfig func() config.AppConfig, extensions *env.Extensions) *SyncServerIPListComponent {
 	return &SyncServerIPListComponent{
-		appConfig: appConfig,
-		stopCh:    make(chan struct{}),
+		appConfig:  appConfig,
+		extensions: extensions,
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
 	}
 }
 
 // SyncServerIPListComponent set timer for update ip list
 // interval : 20m
 type SyncServerIPListComponent struct {
-	appConfig func() config.AppConfig
-	stopCh    chan struct{}
-	stopOnce  sync.Once
-	stopMu    sync.Mutex
+	appConfig  func() config.AppConfig
+	extensions *env.Extensions
+	stopCh     chan struct{}
+	stopOnce   sync.Once
+	stopMu     sync.Mutex
+	doneCh     chan struct{}
 }
 
 // Start 启动同步服务器列表
 func (s *SyncServerIPListComponent) Start() {
 	stopCh := s.ensureStopCh()
-	SyncServerIPList(s.appConfig)
-	log.Debug("syncServerIpListComponent started")
+	defer close(s.ensureDoneCh())
+	ctx, cancel := component.StopContext(stopCh)
+	defer cancel()
+	syncServerIPList(ctx, s.appConfig, s.extensions)
+	var appConfig config.AppConfig
+	if s.appConfig != nil {
+		appConfig = s.appConfig()
+	}
+	logger := appConfig.GetLogger()
+	logger.Log(log.LevelDebug, "syncServerIpListComponent started")
 
-	t2 := time.NewTimer(refreshIPListInterval)
+	t2 := time.NewTimer(s.getRefreshIPListInterval())
 	defer t2.Stop()
 	for {
 		select {
 		case <-stopCh:
-			log.Debug("syncServerIpListComponent stopped")
+			logger.Log(log.LevelDebug, "syncServerIpListComponent stopped")
 			return
 		case <-t2.C:
-			SyncServerIPList(s.appConfig)
-			t2.Reset(refreshIPListInterval)
+			syncServerIPList(ctx, s.appConfig, s.extensions)
+			t2.Reset(s.getRefreshIPListInterval())
 		}
 	}
 }
 
+func (s *SyncServerIPListComponent) getRefreshIPListInterval() time.Duration {
+	var appConfig config.AppConfig
+	if s.appConfig != nil {
+		appConfig = s.appConfig()
+	}
+	return appConfig.GetRefreshIPListInterval()
+}
+
 func (s *SyncServerIPListComponent) Stop() {
 	s.stopOnce.Do(func() {
-		s.stopMu.Lock()
-		defer s.stopMu.Unlock()
-		if s.stopCh != nil {
-			close(s.stopCh)
-		}
+		close(s.ensureStopCh())
 	})
 }
 
+// Done 同步服务器列表定时器退出后关闭
+func (s *SyncServerIPListComponent) Done() <-chan struct{} {
+	return s.ensureDoneCh()
+}
+
 func (s *SyncServerIPListComponent) ensureStopCh() chan struct{} {
 	s.stopMu.Lock()
 	defer s.stopMu.Unlock()
@@ -97,51 +120,117 @@ func (s *SyncServerIPListComponent) ensureStopCh() chan struct{} {
 	return s.stopCh
 }
 
+func (s *SyncServerIPListComponent) ensureDoneCh() chan struct{} {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+	if s.doneCh == nil {
+		s.doneCh = make(chan struct{})
+	}
+	return s.doneCh
+}
+
 // SyncServerIPList sync ip list from server
 // then
 // 1.update agcache
 // 2.store in disk
 func SyncServerIPList(appConfigFunc func() config.AppConfig) (map[string]*config.ServerInfo, error) {
+	return SyncServerIPListContext(context.Background(), appConfigFunc)
+}
+
+// SyncServerIPListContext sync ip list from server, ctx 结束后停止请求
+func SyncServerIPListContext(ctx context.Context, appConfigFunc func() config.AppConfig) (map[string]*config.ServerInfo, error) {
+	return syncServerIPList(ctx, appConfigFunc, nil)
+}
+
+func syncServerIPList(ctx context.Context, appConfigFunc func() config.AppConfig, extensions *env.Extensions) (map[string]*config.ServerInfo, error) {
 	if appConfigFunc == nil {
 		panic("can not find apollo config!please confirm!")
 	}
 
 	appConfig := appConfigFunc()
 	c := &env.ConnectConfig{
-		AppID:  appConfig.AppID,
-		Secret: appConfig.Secret,
+		AppID:         appConfig.AppID,
+		Secret:        appConfig.Secret,
+		Transport:     appConfig.Transport,
+		HTTPTransport: appConfig.HTTPTransport,
+		TLS:           appConfig.TLS,
+		Logger:        appConfig.GetLogger(),
+		Extensions:    extensions,
 	}
 	if appConfig.SyncServerTimeout > 0 {
 		c.Timeout = time.Duration(appConfig.SyncServerTimeout) * time.Second
 	}
-	serverMap, err := http.Request(appConfig.GetServicesConfigURL(), c, &http.CallBack{
+	callBack := &http.CallBack{
 		SuccessCallBack: SyncServerIPListSuccessCallBack,
 		AppConfigFunc:   appConfigFunc,
-	})
+	}
+
+	hosts := appConfig.GetHosts()
+	if len(hosts) <= 1 {
+		hosts = []string{appConfig.GetHost()}
+	}
+	servers := extensions.GetServers()
+	var serverMap interface{}
+	var err error
+	// 按顺序请求可用的 meta server，都处于熔断状态时依次尝试全部 meta server
+	for _, allowed := range []bool{true, false} {
+		attempted := false
+		for _, host := range hosts {
+			if allowed != servers.AllowHost(host) {
+				continue
+			}
+			attempted = true
+			serverMap, err = http.RequestContext(ctx, appConfig.GetServicesConfigURLByHost(host), c, callBack)
+			if serverMap != nil {
+				servers.SetUpHost(host)
+				break
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			c.Logger.Log(log.LevelWarn, "sync server ip list fail", log.KV("host", host), log.KV("error", err))
+			servers.SetDownHost(host)
+		}
+		if serverMap != nil || attempted {
+			break
+		}
+	}
 	if serverMap == nil {
 		return nil, err
 	}
 
 	m := serverMap.(map[string]*config.ServerInfo)
-	server.SetServers(appConfig.GetHost(), m)
+	servers.SetServers(appConfig.GetHost(), m)
 	return m, err
 }
 
 // SyncServerIPListSuccessCallBack 同步服务器列表成功后的回调
 func SyncServerIPListSuccessCallBack(responseBody []byte, callback http.CallBack) (o interface{}, err error) {
-	log.Debug("get all server info:", string(responseBody))
+	logger := log.Global()
+	var weights map[string]int
+	var zones map[string]string
+	if callback.AppConfigFunc != nil {
+		appConfig := callback.AppConfigFunc()
+		logger = appConfig.GetLogger()
+		weights = normalizeAddresses(appConfig.ServerWeights)
+		zones = normalizeAddresses(appConfig.ServerZones)
+	}
+	// 完整的服务列表可能很大，只在开启 debug 日志时转换
+	if logger.Enabled(log.LevelDebug) {
+		logger.Log(log.LevelDebug, "get all server info", log.KV("response", string(responseBody)))
+	}
 
 	tmpServerInfo := make([]*config.ServerInfo, 0)
 
 	err = json.Unmarshal(responseBody, &tmpServerInfo)
 
 	if err != nil {
-		log.Errorf("Unmarshal json Fail, error: %v", err)
+		logger.Log(log.LevelError, "Unmarshal json Fail", log.KV("error", err))
 		return
 	}
 
 	if len(tmpServerInfo) == 0 {
-		log.Info("get no real server!")
+		logger.Log(log.LevelInfo, "get no real server!")
 		return
 	}
 
@@ -150,8 +239,54 @@ func SyncServerIPListSuccessCallBack(responseBody []byte, callback http.CallBack
 		if server == nil {
 			continue
 		}
+		if weight, ok := lookupAddress(weights, server.HomepageURL); ok {
+			server.Weight = weight
+		}
+		if zone, ok := lookupAddress(zones, server.HomepageURL); ok {
+			server.Zone = zone
+		}
 		m[server.HomepageURL] = server
 	}
 	o = m
 	return
 }
+
+// normalizeAddress 将 http://10.0.0.1:8080/ 或 10.0.0.1:8080 形式的节点地址统一为 10.0.0.1:8080
+func normalizeAddress(address string) string {
+	if strings.Contains(address, "://") {
+		if u, err := url.Parse(address); err == nil && u.Host != "" {
+			return strings.ToLower(u.Host)
+		}
+	}
+	return strings.ToLower(strings.TrimSuffix(address, "/"))
+}
+
+// normalizeAddresses 统一配置中节点地址 key 的格式
+func normalizeAddresses[V any](m map[string]V) map[string]V {
+	if len(m) == 0 {
+		return nil
+	}
+	normalized := make(map[string]V, len(m))
+	for address, v := range m {
+		normalized[normalizeAddress(address)] = v
+	}
+	return normalized
+}
+
+// lookupAddress 使用节点的 host:port 精确匹配，匹配不到时使用不带端口的 host 匹配
+func lookupAddress[V any](m map[string]V, homepageURL string) (V, bool) {
+	var zero V
+	if len(m) == 0 {
+		return zero, false
+	}
+	address := normalizeAddress(homepageURL)
+	if v, ok := m[address]; ok {
+		return v, true
+	}
+	if u, err := url.Parse("//" + address); err == nil && u.Port() != "" {
+		if v, ok := m[u.Hostname()]; ok {
+			return v, true
+		}
+	}
+	return zero, false
+}
@@ -15,6 +15,8 @@
 package serverlist
 
 import (
+	nethttp "net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 
@@ -46,6 +48,32 @@ func trySyncServerIPList(t *testing.T) {
 
 }
 
+func TestSyncServerIPListFailover(t *testing.T) {
+	down := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusInternalServerError)
+	}))
+	defer down.Close()
+	ts := runMockServicesConfigServer()
+	defer ts.Close()
+
+	newAppConfig := getTestAppConfig()
+	newAppConfig.IP = down.URL + "," + ts.URL
+	serverMap, err := SyncServerIPList(func() config.AppConfig {
+		return *newAppConfig
+	})
+	Assert(t, err, NilVal())
+	Assert(t, len(serverMap), Equal(10))
+	Assert(t, server.GetServersLen(newAppConfig.GetHost()), Equal(10))
+	Assert(t, server.AllowHost(down.URL+"/"), Equal(false))
+
+	// 失败的 meta server 熔断后直接请求可用的 meta server
+	serverMap, err = SyncServerIPList(func() config.AppConfig {
+		return *newAppConfig
+	})
+	Assert(t, err, NilVal())
+	Assert(t, len(serverMap), Equal(10))
+}
+
 func getTestAppConfig() *config.AppConfig {
 	jsonStr := `{
     "appId": "test",
@@ -69,6 +97,53 @@ func TestSyncServerIpListSuccessCallBack(t *testing.T) {
 	Assert(t, len(m), Equal(10))
 }
 
+func TestSyncServerIpListSuccessCallBackWithWeights(t *testing.T) {
+	appConfig := getTestAppConfig()
+	appConfig.ServerWeights = map[string]int{"10.15.128.102:8080": 5}
+	serverMap, _ := SyncServerIPListSuccessCallBack([]byte(servicesConfigResponseStr), http.CallBack{AppConfigFunc: func() config.AppConfig {
+		return *appConfig
+	}})
+	m := serverMap.(map[string]*config.ServerInfo)
+	Assert(t, m["http://10.15.128.102:8080/"].Weight, Equal(5))
+	Assert(t, m["http://10.15.88.125:8080/"].Weight, Equal(0))
+}
+
+func TestSyncServerIpListSuccessCallBackWithWeightsExactMatch(t *testing.T) {
+	appConfig := getTestAppConfig()
+	appConfig.ServerWeights = map[string]int{
+		"0.14.0.12:8080":            2,
+		"10.14.0.19":                3,
+		"10.15.128.101":             4,
+		"http://10.15.88.124:8080/": 5,
+	}
+	serverMap, _ := SyncServerIPListSuccessCallBack([]byte(servicesConfigResponseStr), http.CallBack{AppConfigFunc: func() config.AppConfig {
+		return *appConfig
+	}})
+	m := serverMap.(map[string]*config.ServerInfo)
+	Assert(t, m["http://10.14.0.12:8080/"].Weight, Equal(0))
+	Assert(t, m["http://10.14.0.193:8080/"].Weight, Equal(0))
+	Assert(t, m["http://10.15.128.101:8080/"].Weight, Equal(4))
+	Assert(t, m["http://10.15.88.124:8080/"].Weight, Equal(5))
+}
+
+func TestSyncServerIpListSuccessCallBackWithZones(t *testing.T) {
+	appConfig := getTestAppConfig()
+	appConfig.ServerZones = map[string]string{"10.15.128.102:8080": "az-a", "10.14.0.11": "az-b", "10.14.0.1:8080": "az-d"}
+	serverMap, _ := SyncServerIPListSuccessCallBack([]byte(`[
+{"appName": "APOLLO-CONFIGSERVICE", "homepageUrl": "http://10.15.128.102:8080/"},
+{"appName": "APOLLO-CONFIGSERVICE", "homepageUrl": "http://10.14.0.11:8080/"},
+{"appName": "APOLLO-CONFIGSERVICE", "homepageUrl": "http://110.14.0.1:8080/"},
+{"appName": "APOLLO-CONFIGSERVICE", "homepageUrl": "http://10.16.0.1:8080/", "zone": "az-c"}
+]`), http.CallBack{AppConfigFunc: func() config.AppConfig {
+		return *appConfig
+	}})
+	m := serverMap.(map[string]*config.ServerInfo)
+	Assert(t, m["http://10.15.128.102:8080/"].Zone, Equal("az-a"))
+	Assert(t, m["http://10.14.0.11:8080/"].Zone, Equal("az-b"))
+	Assert(t, m["http://110.14.0.1:8080/"].Zone, Equal(""))
+	Assert(t, m["http://10.16.0.1:8080/"].Zone, Equal("az-c"))
+}
+
 func TestSetDownNode(t *testing.T) {
 	t.SkipNow()
 	appConfig := getTestAppConfig()
@@ -28,6 +28,8 @@ const (
 	YML ConfigFileFormat = ".yml"
 	//YAML YAML
 	YAML ConfigFileFormat = ".yaml"
+	//TOML TOML
+	TOML ConfigFileFormat = ".toml"
 	// DEFAULT DEFAULT
 	DEFAULT ConfigFileFormat = ""
 )
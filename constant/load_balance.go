@@ -0,0 +1,29 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package constant
+
+// 内置负载均衡策略名称，通过 AppConfig.LoadBalance 选择
+const (
+	//RoundRobin 按节点地址排序后轮询
+	RoundRobin = "roundRobin"
+	//Random 随机
+	Random = "random"
+	//Weighted 按 AppConfig.ServerWeights 配置的权重平滑加权轮询
+	Weighted = "weighted"
+	//LeastOutstanding 选择进行中请求最少的节点
+	LeastOutstanding = "leastOutstanding"
+	//EWMA 选择响应时间指数加权移动平均值最小的节点
+	EWMA = "ewma"
+)
@@ -88,6 +88,9 @@ func Unmarshal(b []byte) (interface{}, error) {
 	if utils.IsNotNil(err) {
 		return nil, err
 	}
+	if err = appConfig.ValidateIntervals(); err != nil {
+		return nil, err
+	}
 	appConfig.Init()
 	return appConfig, nil
 }
@@ -102,6 +102,91 @@ func TestInit(t *testing.T) {
 	//Assert(t, "application", Equal(defaultApolloConfig.NamespaceName))
 }
 
+func TestUnmarshalHTTPTransport(t *testing.T) {
+	c, err := Unmarshal([]byte(`{
+    "appId": "test",
+    "httpTransport": {
+        "maxIdleConns": 10,
+        "maxIdleConnsPerHost": 2,
+        "maxConnsPerHost": 4,
+        "idleConnTimeout": 30,
+        "keepAlive": 15,
+        "disableKeepAlives": true
+    }
+}`))
+	Assert(t, err, NilVal())
+
+	appConfig := c.(*config.AppConfig)
+	Assert(t, appConfig.HTTPTransport, Equal(config.HTTPTransportConfig{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 2,
+		MaxConnsPerHost:     4,
+		IdleConnTimeout:     30,
+		KeepAlive:           15,
+		DisableKeepAlives:   true,
+	}))
+}
+
+func TestUnmarshalTLS(t *testing.T) {
+	c, err := Unmarshal([]byte(`{
+    "appId": "test",
+    "tls": {
+        "caFile": "/etc/apollo/ca.pem",
+        "certFile": "/etc/apollo/client.pem",
+        "keyFile": "/etc/apollo/client.key",
+        "serverName": "apollo.example.com",
+        "minVersion": "1.2"
+    }
+}`))
+	Assert(t, err, NilVal())
+
+	appConfig := c.(*config.AppConfig)
+	Assert(t, appConfig.TLS, Equal(config.TLSConfig{
+		CAFile:     "/etc/apollo/ca.pem",
+		CertFile:   "/etc/apollo/client.pem",
+		KeyFile:    "/etc/apollo/client.key",
+		ServerName: "apollo.example.com",
+		MinVersion: "1.2",
+	}))
+}
+
+func TestUnmarshalLoadBalance(t *testing.T) {
+	c, err := Unmarshal([]byte(`{
+    "appId": "test",
+    "loadBalance": "weighted",
+    "serverWeights": {"10.0.0.1:8080": 3}
+}`))
+	Assert(t, err, NilVal())
+
+	appConfig := c.(*config.AppConfig)
+	Assert(t, appConfig.LoadBalance, Equal("weighted"))
+	Assert(t, appConfig.ServerWeights, Equal(map[string]int{"10.0.0.1:8080": 3}))
+}
+
+func TestUnmarshalIntervals(t *testing.T) {
+	c, err := Unmarshal([]byte(`{
+    "appId": "test",
+    "longPollInterval": "5s",
+    "refreshIPListInterval": 600,
+    "notifyConnectTimeout": "2m",
+    "nextTryConnectPeriod": "1m",
+    "refreshInterval": "10m"
+}`))
+	Assert(t, err, NilVal())
+
+	appConfig := c.(*config.AppConfig)
+	Assert(t, appConfig.GetLongPollInterval(), Equal(5*time.Second))
+	Assert(t, appConfig.GetRefreshIPListInterval(), Equal(10*time.Minute))
+	Assert(t, appConfig.GetNotifyConnectTimeout(), Equal(2*time.Minute))
+	Assert(t, appConfig.GetNextTryConnectPeriod(), Equal(time.Minute))
+	Assert(t, appConfig.GetRefreshInterval(), Equal(10*time.Minute))
+
+	_, err = Unmarshal([]byte(`{"appId": "test", "notifyConnectTimeout": "10s"}`))
+	Assert(t, err, NotNilVal())
+	_, err = Unmarshal([]byte(`{"appId": "test", "longPollInterval": "2x"}`))
+	Assert(t, err, NotNilVal())
+}
+
 func TestGetServicesConfigUrl(t *testing.T) {
 	appConfig := getTestAppConfig()
 	url := appConfig.GetServicesConfigURL()
@@ -41,6 +41,14 @@ func (c *CurrentApolloConfig) Set(namespace string, connConfig *ApolloConnConfig
 	c.configs[namespace] = connConfig
 }
 
+// Remove 移除 namespace 的 apollo 配置
+func (c *CurrentApolloConfig) Remove(namespace string) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	delete(c.configs, namespace)
+}
+
 // GetCurrentApolloConfig 获取Apollo链接配置
 func (c *CurrentApolloConfig) Get() map[string]*ApolloConnConfig {
 	c.l.RLock()
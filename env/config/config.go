@@ -17,13 +17,18 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"sync"
 
+	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/utils"
 )
 
+const zoneEnv = "AGOLLO_ZONE"
+
 var (
 	defaultNotificationID = int64(-1)
 	Comma                 = ","
@@ -48,9 +53,72 @@ type AppConfig struct {
 	Label             string `json:"label"`
 	SyncServerTimeout int    `json:"syncServerTimeout"`
 	// MustStart 可用于控制第一次同步必须成功
-	MustStart               bool `default:"false"`
+	MustStart bool `default:"false"`
+	// Transport 自定义请求 apollo 使用的 http.RoundTripper（如代理、自定义拨号、httptest），设置后 HTTPTransport 与 TLS 不生效
+	Transport http.RoundTripper `json:"-"`
+	// HTTPTransport 请求 apollo 使用的连接池配置，零值使用默认值
+	HTTPTransport HTTPTransportConfig `json:"httpTransport"`
+	// TLS 请求 https 地址时的 TLS 配置，默认校验服务端证书
+	TLS TLSConfig `json:"tls"`
+	// LoadBalance 负载均衡策略名称，为空时使用 extension.SetLoadBalance 设置的负载均衡器
+	LoadBalance string `json:"loadBalance"`
+	// ServerWeights 节点权重，key 为节点地址（如 10.0.0.1:8080），按 host:port 精确匹配，不带端口时匹配该 host 的所有端口，未配置的节点权重为 1
+	ServerWeights map[string]int `json:"serverWeights"`
+	// Zone 客户端所在的可用区，为空时读取环境变量 AGOLLO_ZONE，设置后优先选择相同可用区的节点
+	Zone string `json:"zone"`
+	// ServerZones 节点所在的可用区，key 为节点地址（如 10.0.0.1:8080），匹配规则与 ServerWeights 相同，优先于 meta server 返回的 zone
+	ServerZones map[string]string `json:"serverZones"`
+	// Logger 当前客户端使用的结构化日志，为空时使用全局日志
+	Logger log.StructuredLogger `json:"-"`
+	// LogLevel 当前客户端的日志级别，可选 debug、info、warn、error，为空或无法解析时使用全局日志级别
+	LogLevel string `json:"logLevel"`
+	// LongPollInterval 长轮询的间隔，零值使用默认值 2s
+	LongPollInterval Duration `json:"longPollInterval"`
+	// RefreshIPListInterval 刷新 config service 节点列表的间隔，零值使用默认值 20m
+	RefreshIPListInterval Duration `json:"refreshIPListInterval"`
+	// NotifyConnectTimeout 长轮询与同步配置的请求超时时间，需大于服务端挂起长轮询的 60s，零值使用默认值 10m
+	NotifyConnectTimeout Duration `json:"notifyConnectTimeout"`
+	// NextTryConnectPeriod 节点失效后重新尝试直连 meta server 的间隔，零值使用默认值 30s
+	NextTryConnectPeriod Duration `json:"nextTryConnectPeriod"`
+	// RefreshInterval 定期对账所有 namespace 的间隔，用于修复丢失的变更通知，零值使用默认值 5m，负数表示不开启
+	RefreshInterval         Duration `json:"refreshInterval"`
 	notificationsMap        *notificationsMap
 	currentConnApolloConfig *CurrentApolloConfig
+	// logger Init 时根据 Logger 与 LogLevel 创建的日志
+	logger log.StructuredLogger
+}
+
+// HTTPTransportConfig http 连接池配置，配置相同的客户端共用同一个 http.Transport
+type HTTPTransportConfig struct {
+	// MaxIdleConns 最大空闲连接数，默认 512
+	MaxIdleConns int `json:"maxIdleConns"`
+	// MaxIdleConnsPerHost 每个 host 最大空闲连接数，默认 512
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost"`
+	// MaxConnsPerHost 每个 host 最大连接数，默认不限制
+	MaxConnsPerHost int `json:"maxConnsPerHost"`
+	// IdleConnTimeout 空闲连接超时时间（秒），默认不超时
+	IdleConnTimeout int `json:"idleConnTimeout"`
+	// KeepAlive TCP keep-alive 间隔（秒），默认 60
+	KeepAlive int `json:"keepAlive"`
+	// DisableKeepAlives 禁用 http keep-alive，每个请求使用新连接
+	DisableKeepAlives bool `json:"disableKeepAlives"`
+}
+
+// TLSConfig TLS 配置，证书与私钥均为 PEM 格式的文件路径，创建客户端时校验；
+// CA 证书只在创建连接池时读取，修改后需要重启客户端，客户端证书与私钥修改后自动重新加载
+type TLSConfig struct {
+	// CAFile 校验服务端证书使用的 CA 证书，为空时使用系统根证书
+	CAFile string `json:"caFile"`
+	// CertFile 客户端证书，与 KeyFile 同时设置时启用双向 TLS
+	CertFile string `json:"certFile"`
+	// KeyFile 客户端证书私钥
+	KeyFile string `json:"keyFile"`
+	// ServerName 校验服务端证书使用的主机名，为空时使用请求地址中的主机名
+	ServerName string `json:"serverName"`
+	// MinVersion 最低 TLS 版本，可选 1.0、1.1、1.2、1.3，为空时使用 Go 的默认值
+	MinVersion string `json:"minVersion"`
+	// InsecureSkipVerify 跳过服务端证书校验，仅用于测试环境
+	InsecureSkipVerify bool `json:"insecureSkipVerify"`
 }
 
 // ServerInfo 服务器信息
@@ -59,6 +127,18 @@ type ServerInfo struct {
 	InstanceID  string `json:"instanceId"`
 	HomepageURL string `json:"homepageUrl"`
 	IsDown      bool   `json:"-"`
+	// Weight 节点权重，来自 AppConfig.ServerWeights
+	Weight int `json:"-"`
+	// Zone 节点所在的可用区，来自 meta server 或 AppConfig.ServerZones
+	Zone string `json:"zone"`
+	// Breaker 节点的熔断器，由所属的节点列表在保存节点时设置，为空时按 IsDown 判断是否可以请求
+	Breaker CircuitBreaker `json:"-"`
+}
+
+// CircuitBreaker 节点熔断器
+type CircuitBreaker interface {
+	// Allow 判断是否可以请求节点，熔断超时后放行一个探测请求，熔断器关闭时按 isDown 判断
+	Allow(isDown bool) bool
 }
 
 // GetIsBackupConfig whether backup config after fetch config from apollo
@@ -73,11 +153,63 @@ func (a *AppConfig) GetBackupConfigPath() string {
 	return a.BackupConfigPath
 }
 
-// GetHost GetHost
+// GetZone 获取客户端所在的可用区
+func (a *AppConfig) GetZone() string {
+	if a.Zone != utils.Empty {
+		return a.Zone
+	}
+	return os.Getenv(zoneEnv)
+}
+
+// GetLogger 获取当前客户端使用的结构化日志，Init 后修改 Logger 或 LogLevel 需要重新调用 Init
+func (a *AppConfig) GetLogger() log.StructuredLogger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return a.newLogger()
+}
+
+func (a *AppConfig) newLogger() log.StructuredLogger {
+	logger := a.Logger
+	if logger == nil {
+		logger = log.Global()
+	}
+	if a.LogLevel == utils.Empty {
+		return logger
+	}
+	level, err := log.ParseLevel(a.LogLevel)
+	if err != nil {
+		return logger
+	}
+	return log.WithLevel(logger, level)
+}
+
+// GetHost 获取第一个 meta server 地址，同时作为 config service 节点列表的 key
 func (a *AppConfig) GetHost() string {
-	u, err := url.Parse(a.IP)
+	hosts := a.GetHosts()
+	if len(hosts) == 0 {
+		return normalizeHost(a.IP)
+	}
+	return hosts[0]
+}
+
+// GetHosts 获取所有 meta server 地址，IP 可以配置多个以逗号分隔的地址
+func (a *AppConfig) GetHosts() []string {
+	hosts := make([]string, 0, 1)
+	for _, ip := range strings.Split(a.IP, Comma) {
+		ip = strings.TrimSpace(ip)
+		if ip == utils.Empty {
+			continue
+		}
+		hosts = append(hosts, normalizeHost(ip))
+	}
+	return hosts
+}
+
+func normalizeHost(ip string) string {
+	u, err := url.Parse(ip)
 	if err != nil {
-		return a.IP
+		return ip
 	}
 	if !strings.HasSuffix(u.Path, "/") {
 		return u.String() + "/"
@@ -87,6 +219,7 @@ func (a *AppConfig) GetHost() string {
 
 // Init 初始化notificationsMap
 func (a *AppConfig) Init() {
+	a.logger = a.newLogger()
 	a.currentConnApolloConfig = CreateCurrentApolloConfig()
 	a.initAllNotifications(nil)
 }
@@ -118,6 +251,16 @@ func SplitNamespaces(namespacesStr string, callback func(namespace string)) sync
 	return namespaces
 }
 
+// ContainsNamespace 判断 NamespaceName 中是否包含 namespace
+func (a *AppConfig) ContainsNamespace(namespace string) bool {
+	for _, n := range strings.Split(a.NamespaceName, Comma) {
+		if n == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNotificationsMap 获取notificationsMap
 func (a *AppConfig) GetNotificationsMap() *notificationsMap {
 	return a.notificationsMap
@@ -125,8 +268,13 @@ func (a *AppConfig) GetNotificationsMap() *notificationsMap {
 
 // GetServicesConfigURL 获取服务器列表url
 func (a *AppConfig) GetServicesConfigURL() string {
+	return a.GetServicesConfigURLByHost(a.GetHost())
+}
+
+// GetServicesConfigURLByHost 获取指定 meta server 的服务器列表url
+func (a *AppConfig) GetServicesConfigURLByHost(host string) string {
 	return fmt.Sprintf("%sservices/config?appId=%s&ip=%s",
-		a.GetHost(),
+		host,
 		url.QueryEscape(a.AppID),
 		utils.GetInternal())
 }
@@ -166,6 +314,11 @@ func (n *notificationsMap) UpdateNotify(namespaceName string, notificationID int
 	}
 }
 
+// RemoveNotify 移除 namespace 的 notificationID，长轮询不再监听该 namespace
+func (n *notificationsMap) RemoveNotify(namespaceName string) {
+	n.notifications.Delete(namespaceName)
+}
+
 func (n *notificationsMap) setNotify(namespaceName string, notificationID int64) {
 	n.notifications.Store(namespaceName, notificationID)
 }
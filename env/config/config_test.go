@@ -16,11 +16,14 @@ package config
 
 import (
 	"encoding/json"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	. "github.com/tevid/gohamcrest"
 
+	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/utils"
 )
 
@@ -82,6 +85,61 @@ func TestGetHost(t *testing.T) {
 	appConfig.IP = ip
 }
 
+func TestGetHosts(t *testing.T) {
+	c := &AppConfig{IP: " http://meta1:8080 ,, http://meta2:8080/"}
+	Assert(t, c.GetHosts(), Equal([]string{"http://meta1:8080/", "http://meta2:8080/"}))
+	Assert(t, c.GetHost(), Equal("http://meta1:8080/"))
+	Assert(t, strings.HasPrefix(c.GetServicesConfigURLByHost("http://meta2:8080/"), "http://meta2:8080/services/config?appId="), Equal(true))
+
+	c.IP = ""
+	Assert(t, len(c.GetHosts()), Equal(0))
+}
+
+type testStructuredLogger struct {
+	msgs []string
+}
+
+func (l *testStructuredLogger) Enabled(level log.Level) bool {
+	return true
+}
+
+func (l *testStructuredLogger) Log(level log.Level, msg string, fields ...log.Field) {
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestGetLogger(t *testing.T) {
+	c := &AppConfig{}
+	Assert(t, c.GetLogger().Enabled(log.LevelDebug), Equal(true))
+
+	c.LogLevel = "unknown"
+	Assert(t, c.GetLogger().Enabled(log.LevelDebug), Equal(true))
+
+	l := &testStructuredLogger{}
+	c.Logger = l
+	c.LogLevel = "warn"
+	logger := c.GetLogger()
+	Assert(t, logger.Enabled(log.LevelInfo), Equal(false))
+	logger.Log(log.LevelInfo, "info")
+	logger.Log(log.LevelError, "error")
+	Assert(t, l.msgs, Equal([]string{"error"}))
+
+	c.Init()
+	Assert(t, c.GetLogger() == c.GetLogger(), Equal(true))
+	Assert(t, c.GetLogger().Enabled(log.LevelInfo), Equal(false))
+}
+
+func TestGetZone(t *testing.T) {
+	c := &AppConfig{}
+	t.Setenv(zoneEnv, "")
+	Assert(t, c.GetZone(), Equal(""))
+
+	t.Setenv(zoneEnv, "az-env")
+	Assert(t, c.GetZone(), Equal("az-env"))
+
+	c.Zone = "az-a"
+	Assert(t, c.GetZone(), Equal("az-a"))
+}
+
 func TestSplitNamespaces(t *testing.T) {
 	w := &sync.WaitGroup{}
 	w.Add(3)
@@ -117,3 +175,57 @@ func TestNotificationsMap(t *testing.T) {
 	Assert(t, noExistID, Equal(int64(0)))
 
 }
+
+func TestContainsNamespaceAndRemoveNotify(t *testing.T) {
+	c := &AppConfig{NamespaceName: "application,db"}
+	c.Init()
+	Assert(t, c.ContainsNamespace("db"), Equal(true))
+	Assert(t, c.ContainsNamespace("d"), Equal(false))
+	Assert(t, c.ContainsNamespace(""), Equal(false))
+
+	c.GetNotificationsMap().UpdateNotify("db", 5)
+	Assert(t, c.GetNotificationsMap().GetNotify("db"), Equal(int64(5)))
+	c.GetNotificationsMap().RemoveNotify("db")
+	Assert(t, c.GetNotificationsMap().GetNotify("db"), Equal(int64(0)))
+	Assert(t, c.GetNotificationsMap().GetNotify("application"), Equal(int64(-1)))
+}
+
+func TestGetIntervals(t *testing.T) {
+	appConfig := &AppConfig{}
+	Assert(t, appConfig.GetLongPollInterval(), Equal(2*time.Second))
+	Assert(t, appConfig.GetRefreshIPListInterval(), Equal(20*time.Minute))
+	Assert(t, appConfig.GetNotifyConnectTimeout(), Equal(10*time.Minute))
+	Assert(t, appConfig.GetNextTryConnectPeriod(), Equal(30*time.Second))
+	Assert(t, appConfig.GetRefreshInterval(), Equal(5*time.Minute))
+	Assert(t, appConfig.ValidateIntervals(), NilVal())
+
+	appConfig.LongPollInterval = Duration(time.Second)
+	appConfig.RefreshInterval = Duration(time.Minute)
+	Assert(t, appConfig.GetLongPollInterval(), Equal(time.Second))
+	Assert(t, appConfig.GetRefreshInterval(), Equal(time.Minute))
+	Assert(t, appConfig.ValidateIntervals(), NilVal())
+}
+
+func TestValidateIntervals(t *testing.T) {
+	Assert(t, (&AppConfig{LongPollInterval: Duration(-time.Second)}).ValidateIntervals(), NotNilVal())
+	Assert(t, (&AppConfig{NotifyConnectTimeout: Duration(30 * time.Second)}).ValidateIntervals(), NotNilVal())
+	Assert(t, (&AppConfig{NotifyConnectTimeout: Duration(90 * time.Second)}).ValidateIntervals(), NilVal())
+	Assert(t, (&AppConfig{NextTryConnectPeriod: Duration(time.Millisecond)}).ValidateIntervals(), NotNilVal())
+	Assert(t, (&AppConfig{RefreshInterval: Duration(time.Millisecond)}).ValidateIntervals(), NotNilVal())
+	Assert(t, (&AppConfig{RefreshInterval: Duration(-1)}).ValidateIntervals(), NilVal())
+	Assert(t, (&AppConfig{RefreshInterval: Duration(-1)}).GetRefreshInterval() < 0, Equal(true))
+}
+
+func TestDurationJSON(t *testing.T) {
+	var d Duration
+	Assert(t, json.Unmarshal([]byte(`"1m30s"`), &d), NilVal())
+	Assert(t, time.Duration(d), Equal(90*time.Second))
+	Assert(t, json.Unmarshal([]byte(`2.5`), &d), NilVal())
+	Assert(t, time.Duration(d), Equal(2500*time.Millisecond))
+	Assert(t, json.Unmarshal([]byte(`"abc"`), &d), NotNilVal())
+	Assert(t, json.Unmarshal([]byte(`true`), &d), NotNilVal())
+
+	b, err := json.Marshal(Duration(5 * time.Minute))
+	Assert(t, err, NilVal())
+	Assert(t, string(b), Equal(`"5m0s"`))
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultLongPollInterval 长轮询的间隔
+	defaultLongPollInterval = 2 * time.Second
+	// defaultRefreshIPListInterval 刷新 config service 节点列表的间隔
+	defaultRefreshIPListInterval = 20 * time.Minute
+	// defaultNotifyConnectTimeout 长轮询与同步配置的请求超时时间
+	defaultNotifyConnectTimeout = 10 * time.Minute
+	// defaultNextTryConnectPeriod 节点失效后重新尝试直连 meta server 的间隔
+	defaultNextTryConnectPeriod = 30 * time.Second
+	// defaultRefreshInterval 定期对账所有 namespace 的间隔，与 Java 客户端的 apollo.refreshInterval 一致
+	defaultRefreshInterval = 5 * time.Minute
+
+	// longPollHoldTime config service 挂起长轮询请求的最长时间
+	longPollHoldTime = 60 * time.Second
+)
+
+// Duration 时间间隔，JSON 中可以使用 "30s"、"5m" 等字符串，也可以使用表示秒数的数字
+type Duration time.Duration
+
+// MarshalJSON 序列化为 "30s" 格式的字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 解析 "30s" 格式的字符串或表示秒数的数字
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		duration, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = Duration(duration)
+		return nil
+	}
+	var seconds float64
+	if err := json.Unmarshal(b, &seconds); err != nil {
+		return fmt.Errorf("invalid duration %s", b)
+	}
+	*d = Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// GetLongPollInterval 获取长轮询的间隔，默认 2s
+func (a *AppConfig) GetLongPollInterval() time.Duration {
+	return durationOrDefault(a.LongPollInterval, defaultLongPollInterval)
+}
+
+// GetRefreshIPListInterval 获取刷新 config service 节点列表的间隔，默认 20m
+func (a *AppConfig) GetRefreshIPListInterval() time.Duration {
+	return durationOrDefault(a.RefreshIPListInterval, defaultRefreshIPListInterval)
+}
+
+// GetNotifyConnectTimeout 获取长轮询与同步配置的请求超时时间，默认 10m
+func (a *AppConfig) GetNotifyConnectTimeout() time.Duration {
+	return durationOrDefault(a.NotifyConnectTimeout, defaultNotifyConnectTimeout)
+}
+
+// GetNextTryConnectPeriod 获取节点失效后重新尝试直连 meta server 的间隔，默认 30s
+func (a *AppConfig) GetNextTryConnectPeriod() time.Duration {
+	return durationOrDefault(a.NextTryConnectPeriod, defaultNextTryConnectPeriod)
+}
+
+// GetRefreshInterval 获取定期对账所有 namespace 的间隔，默认 5m，负数表示不开启定期对账
+func (a *AppConfig) GetRefreshInterval() time.Duration {
+	if a.RefreshInterval < 0 {
+		return time.Duration(a.RefreshInterval)
+	}
+	return durationOrDefault(a.RefreshInterval, defaultRefreshInterval)
+}
+
+// ValidateIntervals 校验时间间隔配置，零值表示使用默认值，refreshInterval 为负数时表示不开启定期对账
+func (a *AppConfig) ValidateIntervals() error {
+	intervals := []struct {
+		name  string
+		value Duration
+	}{
+		{"longPollInterval", a.LongPollInterval},
+		{"refreshIPListInterval", a.RefreshIPListInterval},
+		{"notifyConnectTimeout", a.NotifyConnectTimeout},
+		{"nextTryConnectPeriod", a.NextTryConnectPeriod},
+	}
+	for _, interval := range intervals {
+		if interval.value < 0 {
+			return fmt.Errorf("%s can not be negative: %s", interval.name, time.Duration(interval.value))
+		}
+	}
+	// 超时时间需要大于服务端挂起长轮询的时间，否则每次长轮询都会超时
+	if a.NotifyConnectTimeout > 0 && time.Duration(a.NotifyConnectTimeout) <= longPollHoldTime {
+		return fmt.Errorf("notifyConnectTimeout must be greater than %s: %s", longPollHoldTime, time.Duration(a.NotifyConnectTimeout))
+	}
+	// 节点恢复时间以秒为单位记录
+	if a.NextTryConnectPeriod > 0 && time.Duration(a.NextTryConnectPeriod) < time.Second {
+		return fmt.Errorf("nextTryConnectPeriod must be at least 1s: %s", time.Duration(a.NextTryConnectPeriod))
+	}
+	if a.RefreshInterval > 0 && time.Duration(a.RefreshInterval) < time.Second {
+		return fmt.Errorf("refreshInterval must be at least 1s: %s", time.Duration(a.RefreshInterval))
+	}
+	return nil
+}
+
+func durationOrDefault(d Duration, defaultValue time.Duration) time.Duration {
+	if d > 0 {
+		return time.Duration(d)
+	}
+	return defaultValue
+}
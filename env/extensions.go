@@ -0,0 +1,89 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/constant"
+	"github.com/apolloconfig/agollo/v5/env/file"
+	"github.com/apolloconfig/agollo/v5/env/server"
+	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/protocol/auth"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+)
+
+// Extensions 单个客户端独占的组件，同一进程中的多个客户端互不影响；
+// 字段为空时使用 server 包中的全局节点列表与 extension 包中设置的全局组件，nil 表示全部使用全局组件
+type Extensions struct {
+	// Servers config service 节点列表
+	Servers *server.Servers
+	// FileHandler 备份文件读写
+	FileHandler file.FileHandler
+	// LoadBalance 负载均衡器，优先于 AppConfig.LoadBalance 指定的负载均衡器
+	LoadBalance cluster.LoadBalance
+	// HTTPAuth 请求 apollo 的授权
+	HTTPAuth auth.HTTPAuth
+	// FormatParsers namespace 内容解析器，未配置的格式使用全局解析器
+	FormatParsers map[constant.ConfigFileFormat]parse.ContentParser
+}
+
+// NewExtensions 创建使用独立节点列表的 Extensions，其他组件使用全局组件
+func NewExtensions() *Extensions {
+	return &Extensions{
+		Servers: server.NewServers(),
+	}
+}
+
+// GetServers 获取节点列表
+func (e *Extensions) GetServers() *server.Servers {
+	if e == nil || e.Servers == nil {
+		return server.GetDefaultServers()
+	}
+	return e.Servers
+}
+
+// GetFileHandler 获取备份文件读写组件
+func (e *Extensions) GetFileHandler() file.FileHandler {
+	if e == nil || e.FileHandler == nil {
+		return extension.GetFileHandler()
+	}
+	return e.FileHandler
+}
+
+// GetLoadBalance 获取客户端独占的负载均衡器，未设置时返回 nil
+func (e *Extensions) GetLoadBalance() cluster.LoadBalance {
+	if e == nil {
+		return nil
+	}
+	return e.LoadBalance
+}
+
+// GetHTTPAuth 获取请求授权组件
+func (e *Extensions) GetHTTPAuth() auth.HTTPAuth {
+	if e == nil || e.HTTPAuth == nil {
+		return extension.GetHTTPAuth()
+	}
+	return e.HTTPAuth
+}
+
+// GetFormatParser 获取 namespace 内容解析器
+func (e *Extensions) GetFormatParser(key constant.ConfigFileFormat) parse.ContentParser {
+	if e != nil {
+		if parser := e.FormatParsers[key]; parser != nil {
+			return parser
+		}
+	}
+	return extension.GetFormatParser(key)
+}
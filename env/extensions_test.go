@@ -0,0 +1,65 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/constant"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/server"
+	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+)
+
+type testParser struct {
+}
+
+func (*testParser) Parse(configContent interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func TestExtensionsDefault(t *testing.T) {
+	var e *Extensions
+	Assert(t, e.GetServers(), Equal(server.GetDefaultServers()))
+	Assert(t, e.GetFileHandler(), Equal(extension.GetFileHandler()))
+	Assert(t, e.GetLoadBalance(), NilVal())
+	Assert(t, e.GetHTTPAuth(), Equal(extension.GetHTTPAuth()))
+	Assert(t, e.GetFormatParser(constant.Properties), Equal(extension.GetFormatParser(constant.Properties)))
+}
+
+func TestExtensionsServers(t *testing.T) {
+	e1 := NewExtensions()
+	e2 := NewExtensions()
+	e1.GetServers().SetServers("http://localhost:8080", map[string]*config.ServerInfo{
+		"http://node1": {HomepageURL: "http://node1"},
+	})
+	Assert(t, e1.GetServers().GetServersLen("http://localhost:8080"), Equal(1))
+	Assert(t, e2.GetServers().GetServersLen("http://localhost:8080"), Equal(0))
+	Assert(t, server.GetServersLen("http://localhost:8080"), Equal(0))
+}
+
+func TestExtensionsFormatParser(t *testing.T) {
+	p := &testParser{}
+	e := &Extensions{
+		FormatParsers: map[constant.ConfigFileFormat]parse.ContentParser{
+			constant.YML: p,
+		},
+	}
+	Assert(t, e.GetFormatParser(constant.YML), Equal(p))
+	Assert(t, e.GetFormatParser(constant.JSON), Equal(extension.GetFormatParser(constant.JSON)))
+}
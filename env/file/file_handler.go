@@ -15,7 +15,10 @@
 package file
 
 import (
+	"github.com/apolloconfig/agollo/v5/component/log"
+	"github.com/apolloconfig/agollo/v5/constant"
 	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
 )
 
 // FileHandler 备份文件读写
@@ -24,3 +27,19 @@ type FileHandler interface {
 	GetConfigFile(configDir string, appID string, namespace string) string
 	LoadConfigFile(configDir string, appID string, namespace string) (*config.ApolloConfig, error)
 }
+
+// ClientOptions 创建客户端时传给备份文件处理器的客户端配置
+type ClientOptions struct {
+	// Logger 客户端使用的结构化日志
+	Logger log.StructuredLogger
+	// Cluster 客户端的集群名
+	Cluster string
+	// FormatParser 获取客户端使用的 namespace 内容解析器
+	FormatParser func(format constant.ConfigFileFormat) parse.ContentParser
+}
+
+// ClientFileHandler 可按客户端配置派生的备份文件处理器，创建客户端时使用 WithClient 返回的处理器
+type ClientFileHandler interface {
+	FileHandler
+	WithClient(options ClientOptions) FileHandler
+}
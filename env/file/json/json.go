@@ -24,6 +24,7 @@ import (
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	jsonConfig "github.com/apolloconfig/agollo/v5/env/config/json"
+	"github.com/apolloconfig/agollo/v5/env/file"
 )
 
 // Suffix 默认文件保存类型
@@ -45,6 +46,20 @@ var (
 
 // FileHandler 默认备份文件读写
 type FileHandler struct {
+	// logger 输出日志使用的结构化日志，为空时使用全局日志
+	logger log.StructuredLogger
+}
+
+// WithClient 返回使用客户端日志的备份文件处理器
+func (fileHandler *FileHandler) WithClient(options file.ClientOptions) file.FileHandler {
+	return &FileHandler{logger: options.Logger}
+}
+
+func (fileHandler *FileHandler) getLogger() log.StructuredLogger {
+	if fileHandler == nil || fileHandler.logger == nil {
+		return log.Global()
+	}
+	return fileHandler.logger
 }
 
 // WriteConfigFile write config to file
@@ -58,7 +73,7 @@ func (fileHandler *FileHandler) createDir(configPath string) error {
 	if !configFileDirMap[configPath] {
 		err := os.MkdirAll(configPath, os.ModePerm)
 		if err != nil && !os.IsExist(err) {
-			log.Errorf("Create backup dir:%s fail, error:%v", configPath, err)
+			fileHandler.getLogger().Log(log.LevelError, "create backup dir fail", log.KV("path", configPath), log.KV("error", err))
 			return err
 		}
 		configFileDirMap[configPath] = true
@@ -78,24 +93,25 @@ func (fileHandler *FileHandler) WriteConfigFile(config *config.ApolloConfig, con
 // GetConfigFile get real config file
 func (fileHandler *FileHandler) GetConfigFile(configDir string, appID string, namespace string) string {
 	key := fmt.Sprintf("%s-%s", appID, namespace)
+	// 以目录、appId 与 namespace 区分缓存，避免多个客户端的同名 namespace 互相覆盖
+	cacheKey := fmt.Sprintf("%s/%s", configDir, key)
 	configFileMapLock.Lock()
 	defer configFileMapLock.Unlock()
-	fullPath := configFileMap[key]
+	fullPath := configFileMap[cacheKey]
 	if fullPath == "" {
-		filePath := fmt.Sprintf("%s%s", key, Suffix)
+		fullPath = fmt.Sprintf("%s%s", key, Suffix)
 		if configDir != "" {
-			configFileMap[namespace] = fmt.Sprintf("%s/%s", configDir, filePath)
-		} else {
-			configFileMap[namespace] = filePath
+			fullPath = fmt.Sprintf("%s/%s", configDir, fullPath)
 		}
+		configFileMap[cacheKey] = fullPath
 	}
-	return configFileMap[namespace]
+	return fullPath
 }
 
 // LoadConfigFile load config from file
 func (fileHandler *FileHandler) LoadConfigFile(configDir string, appID string, namespace string) (*config.ApolloConfig, error) {
 	configFilePath := fileHandler.GetConfigFile(configDir, appID, namespace)
-	log.Infof("load config file from: %s", configFilePath)
+	fileHandler.getLogger().Log(log.LevelInfo, "load config file", log.KV("path", configFilePath))
 	c, e := jsonFileConfig.Load(configFilePath, func(b []byte) (interface{}, error) {
 		config := &config.ApolloConfig{}
 		e := json.NewDecoder(bytes.NewBuffer(b)).Decode(config)
@@ -103,7 +119,7 @@ func (fileHandler *FileHandler) LoadConfigFile(configDir string, appID string, n
 	})
 
 	if c == nil || e != nil {
-		log.Errorf("loadConfigFile fail, error:%v", e)
+		fileHandler.getLogger().Log(log.LevelError, "load config file fail", log.KV("path", configFilePath), log.KV("error", e))
 		return nil, e
 	}
 
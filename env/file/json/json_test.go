@@ -42,6 +42,14 @@ func TestCreateDir(t *testing.T) {
 	os.RemoveAll(configPath)
 }
 
+func TestGetConfigFileWithDifferentApp(t *testing.T) {
+	f := &FileHandler{}
+	Assert(t, f.GetConfigFile("dir1", "app1", "application"), Equal("dir1/app1-application.json"))
+	Assert(t, f.GetConfigFile("dir2", "app2", "application"), Equal("dir2/app2-application.json"))
+	Assert(t, f.GetConfigFile("", "app1", "application"), Equal("app1-application.json"))
+	Assert(t, f.GetConfigFile("dir1", "app1", "application"), Equal("dir1/app1-application.json"))
+}
+
 func TestJSONFileHandler_WriteConfigDirFile(t *testing.T) {
 	extension.SetFileHandler(&FileHandler{})
 	configPath := "json-conf"
@@ -17,11 +17,16 @@ package json
 import (
 	"fmt"
 	"os"
+	"path"
 	"sync"
 
 	"github.com/apolloconfig/agollo/v5/component/log"
+	"github.com/apolloconfig/agollo/v5/constant"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	"github.com/apolloconfig/agollo/v5/env/file"
+	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+	"github.com/apolloconfig/agollo/v5/utils/parse/properties"
 )
 
 var (
@@ -32,17 +37,37 @@ var (
 // rawFileHandler 写入备份文件时，同时写入原始内容和namespace类型
 type rawFileHandler struct {
 	*FileHandler
+	// cluster 读取原始内容文件时使用的集群名
+	cluster string
+	// formatParser 获取解析原始内容使用的解析器，为空时使用全局解析器
+	formatParser func(format constant.ConfigFileFormat) parse.ContentParser
 }
 
-func writeWithRaw(config *config.ApolloConfig, configDir string) error {
-	filePath := ""
+// WithClient 返回使用客户端日志、集群名及解析器的 rawFileHandler
+func (fileHandler *rawFileHandler) WithClient(options file.ClientOptions) file.FileHandler {
+	return &rawFileHandler{
+		FileHandler:  &FileHandler{logger: options.Logger},
+		cluster:      options.Cluster,
+		formatParser: options.FormatParser,
+	}
+}
+
+func (fileHandler *rawFileHandler) getFormatParser(format constant.ConfigFileFormat) parse.ContentParser {
+	if fileHandler.formatParser != nil {
+		return fileHandler.formatParser(format)
+	}
+	return extension.GetFormatParser(format)
+}
+
+func getRawFilePath(configDir string, namespace string) string {
 	if configDir != "" {
-		filePath = fmt.Sprintf("%s/%s", configDir, config.NamespaceName)
-	} else {
-		filePath = config.NamespaceName
+		return fmt.Sprintf("%s/%s", configDir, namespace)
 	}
+	return namespace
+}
 
-	file, e := os.Create(filePath)
+func writeWithRaw(config *config.ApolloConfig, configDir string) error {
+	file, e := os.Create(getRawFilePath(configDir, config.NamespaceName))
 	if e != nil {
 		return e
 	}
@@ -65,11 +90,64 @@ func (fileHandler *rawFileHandler) WriteConfigFile(config *config.ApolloConfig,
 
 	err = writeWithRaw(config, configPath)
 	if err != nil {
-		log.Errorf("writeWithRaw fail! error:%v", err)
+		fileHandler.getLogger().Log(log.LevelError, "write raw backup file fail", log.KV("namespace", config.NamespaceName), log.KV("error", err))
 	}
 	return jsonFileConfig.Write(config, fileHandler.GetConfigFile(configPath, config.AppID, config.NamespaceName))
 }
 
+// LoadConfigFile 优先读取 json 备份文件，不存在时读取原始内容文件并按 namespace 类型解析
+func (fileHandler *rawFileHandler) LoadConfigFile(configDir string, appID string, namespace string) (*config.ApolloConfig, error) {
+	c, err := fileHandler.FileHandler.LoadConfigFile(configDir, appID, namespace)
+	if err == nil {
+		return c, nil
+	}
+
+	c, rawErr := fileHandler.loadWithRaw(configDir, appID, namespace)
+	if rawErr != nil {
+		fileHandler.getLogger().Log(log.LevelError, "load raw backup file fail", log.KV("namespace", namespace), log.KV("error", rawErr))
+		return nil, err
+	}
+	return c, nil
+}
+
+// loadWithRaw 读取原始内容文件，无后缀的 namespace 按 properties 格式解析；
+// 没有 content 的配置写入的原始内容文件为空，视为没有备份
+func (fileHandler *rawFileHandler) loadWithRaw(configDir string, appID string, namespace string) (*config.ApolloConfig, error) {
+	rawFilePath := getRawFilePath(configDir, namespace)
+	b, err := os.ReadFile(rawFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("raw backup file is empty: %s", rawFilePath)
+	}
+
+	var parser parse.ContentParser = &properties.Parser{}
+	format := constant.ConfigFileFormat(path.Ext(namespace))
+	if format != constant.DEFAULT {
+		parser = fileHandler.getFormatParser(format)
+	}
+
+	content := string(b)
+	c := &config.ApolloConfig{}
+	c.Init(appID, fileHandler.cluster, namespace)
+	c.Configurations = map[string]interface{}{
+		"content": content,
+	}
+	if parser == nil {
+		return c, nil
+	}
+
+	m, err := parser.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(m) > 0 {
+		c.Configurations = m
+	}
+	return c, nil
+}
+
 // GetRawFileHandler 获取 rawFileHandler 实例
 func GetRawFileHandler() file.FileHandler {
 	rawOnce.Do(func() {
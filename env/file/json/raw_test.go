@@ -20,9 +20,32 @@ import (
 
 	. "github.com/tevid/gohamcrest"
 
+	"github.com/apolloconfig/agollo/v5/component/log"
+	"github.com/apolloconfig/agollo/v5/constant"
+	"github.com/apolloconfig/agollo/v5/env/file"
 	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
 )
 
+type testParser struct {
+}
+
+func (p *testParser) Parse(configContent interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{"parsed": configContent}, nil
+}
+
+type testStructuredLogger struct {
+	msgs []string
+}
+
+func (l *testStructuredLogger) Enabled(level log.Level) bool {
+	return true
+}
+
+func (l *testStructuredLogger) Log(level log.Level, msg string, fields ...log.Field) {
+	l.msgs = append(l.msgs, msg)
+}
+
 func TestRawHandler_WriteConfigDirFile(t *testing.T) {
 	extension.SetFileHandler(&rawFileHandler{})
 	configPath := "raw-conf"
@@ -99,3 +122,67 @@ func TestGetRawFileHandler(t *testing.T) {
 	fileHandler := GetRawFileHandler()
 	Assert(t, handler, Equal(fileHandler))
 }
+
+func TestRawHandler_LoadConfigFileWithRaw(t *testing.T) {
+	configPath := "raw-load-conf"
+	os.RemoveAll(configPath)
+	err := os.MkdirAll(configPath, os.ModePerm)
+	Assert(t, err, NilVal())
+	defer os.RemoveAll(configPath)
+
+	err = os.WriteFile(configPath+"/raw-application", []byte("# backup\nkey1=value1\nkey2 = a \\\n  b\n"), os.ModePerm)
+	Assert(t, err, NilVal())
+
+	config, err := GetRawFileHandler().LoadConfigFile(configPath, "100004458", "raw-application")
+	Assert(t, err, NilVal())
+	Assert(t, config.AppID, Equal("100004458"))
+	Assert(t, config.NamespaceName, Equal("raw-application"))
+	Assert(t, config.Configurations["key1"], Equal("value1"))
+	Assert(t, config.Configurations["key2"], Equal("a b"))
+
+	config, err = GetRawFileHandler().LoadConfigFile(configPath, "100004458", "raw-not-exist")
+	Assert(t, err, NotNilVal())
+	Assert(t, config, NilVal())
+
+	err = os.WriteFile(configPath+"/raw-empty", []byte{}, os.ModePerm)
+	Assert(t, err, NilVal())
+	config, err = GetRawFileHandler().LoadConfigFile(configPath, "100004458", "raw-empty")
+	Assert(t, err, NotNilVal())
+	Assert(t, config, NilVal())
+}
+
+func TestRawHandler_WithClient(t *testing.T) {
+	logger := &testStructuredLogger{}
+	handler := GetRawFileHandler().(file.ClientFileHandler).WithClient(file.ClientOptions{Logger: logger})
+	Assert(t, handler == GetRawFileHandler(), Equal(false))
+
+	config, err := handler.LoadConfigFile("raw-client-conf", "100004458", "raw-not-exist")
+	Assert(t, err, NotNilVal())
+	Assert(t, config, NilVal())
+	Assert(t, logger.msgs, Equal([]string{"load config file", "load config file fail", "load raw backup file fail"}))
+}
+
+func TestRawHandler_LoadConfigFileWithClient(t *testing.T) {
+	configPath := "raw-client-load-conf"
+	os.RemoveAll(configPath)
+	err := os.MkdirAll(configPath, os.ModePerm)
+	Assert(t, err, NilVal())
+	defer os.RemoveAll(configPath)
+
+	err = os.WriteFile(configPath+"/raw-application.yml", []byte("content"), os.ModePerm)
+	Assert(t, err, NilVal())
+
+	handler := GetRawFileHandler().(file.ClientFileHandler).WithClient(file.ClientOptions{
+		Cluster: "dev",
+		FormatParser: func(format constant.ConfigFileFormat) parse.ContentParser {
+			if format == constant.YML {
+				return &testParser{}
+			}
+			return nil
+		},
+	})
+	config, err := handler.LoadConfigFile(configPath, "100004458", "raw-application.yml")
+	Assert(t, err, NilVal())
+	Assert(t, config.Cluster, Equal("dev"))
+	Assert(t, config.Configurations["parsed"], Equal("content"))
+}
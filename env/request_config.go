@@ -15,7 +15,11 @@
 package env
 
 import (
+	"net/http"
 	"time"
+
+	"github.com/apolloconfig/agollo/v5/component/log"
+	"github.com/apolloconfig/agollo/v5/env/config"
 )
 
 // ConnectConfig 网络请求配置
@@ -30,4 +34,16 @@ type ConnectConfig struct {
 	AppID string
 	//密钥
 	Secret string
+	//自定义 http.RoundTripper，为空时根据 HTTPTransport 创建
+	Transport http.RoundTripper
+	//连接池配置
+	HTTPTransport config.HTTPTransportConfig
+	//TLS 配置
+	TLS config.TLSConfig
+	//是否为长轮询请求，长轮询请求的耗时不反馈给负载均衡器
+	IsLongPoll bool
+	//结构化日志，为空时使用全局日志
+	Logger log.StructuredLogger
+	//客户端独占的组件，为空时使用全局组件
+	Extensions *Extensions
 }
@@ -0,0 +1,223 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/extension"
+)
+
+// State 节点熔断器状态
+type State int
+
+const (
+	// StateClosed 节点正常，允许请求
+	StateClosed State = iota
+	// StateOpen 节点熔断，拒绝请求
+	StateOpen
+	// StateHalfOpen 熔断超时后允许一个探测请求，成功则恢复，失败则重新熔断
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+var (
+	// breakerLock 保护熔断器参数
+	breakerLock sync.Mutex
+	//连续失败多少次后熔断
+	failureThreshold = 1
+	//熔断多久后允许探测请求
+	openTimeout = 10 * time.Second
+)
+
+// circuitBreaker 单个节点的熔断器
+type circuitBreaker struct {
+	host     string
+	lock     sync.Mutex
+	state    State
+	failures int
+	// openedAt 熔断开始时间
+	openedAt time.Time
+	// probeAt 半开状态下探测请求的开始时间，零值表示没有进行中的探测请求
+	probeAt time.Time
+}
+
+// SetCircuitBreaker 设置熔断器参数：连续失败 threshold 次后熔断，熔断 timeout 后允许探测请求，对所有节点列表生效
+func SetCircuitBreaker(threshold int, timeout time.Duration) {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+	if threshold > 0 {
+		failureThreshold = threshold
+	}
+	if timeout > 0 {
+		openTimeout = timeout
+	}
+}
+
+func getCircuitBreaker() (int, time.Duration) {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+	return failureThreshold, openTimeout
+}
+
+// AllowRequest 判断是否可以请求该节点，供 cluster.LoadBalance 选择节点时使用，使用节点所属节点列表的熔断器
+// 熔断超时后会转为半开状态并放行一个探测请求，请求结果需要通过 SetDownNode 或 SetUpNode 反馈
+func AllowRequest(server *config.ServerInfo) bool {
+	if server == nil {
+		return false
+	}
+	if server.Breaker != nil {
+		return server.Breaker.Allow(server.IsDown)
+	}
+	return !server.IsDown
+}
+
+// AllowHost 判断是否可以请求全局节点列表中的该地址（如 meta server），请求结果需要通过 SetDownHost 或 SetUpHost 反馈
+func AllowHost(host string) bool {
+	return defaultServers.AllowHost(host)
+}
+
+// SetDownHost 记录全局节点列表中的地址请求失败
+func SetDownHost(host string) {
+	defaultServers.SetDownHost(host)
+}
+
+// SetUpHost 记录全局节点列表中的地址请求成功
+func SetUpHost(host string) {
+	defaultServers.SetUpHost(host)
+}
+
+// GetNodeState 获取全局节点列表中的节点熔断器状态
+func GetNodeState(serverHost string) State {
+	return defaultServers.GetNodeState(serverHost)
+}
+
+// AllowHost 判断是否可以请求该地址（如 meta server），请求结果需要通过 SetDownHost 或 SetUpHost 反馈
+func (s *Servers) AllowHost(host string) bool {
+	s.lock.Lock()
+	b := s.breakers[hostPort(host)]
+	s.lock.Unlock()
+	if b == nil {
+		return true
+	}
+	return b.Allow(false)
+}
+
+// SetDownHost 记录地址请求失败
+func (s *Servers) SetDownHost(host string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.getBreaker(host).onFailure()
+}
+
+// SetUpHost 记录地址请求成功
+func (s *Servers) SetUpHost(host string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if b := s.breakers[hostPort(host)]; b != nil {
+		b.onSuccess()
+	}
+}
+
+// GetNodeState 获取节点熔断器状态
+func (s *Servers) GetNodeState(serverHost string) State {
+	s.lock.Lock()
+	b := s.breakers[hostPort(serverHost)]
+	s.lock.Unlock()
+	if b == nil {
+		return StateClosed
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+// getBreaker 获取节点的熔断器，不存在时创建，需持有 s.lock
+func (s *Servers) getBreaker(serverHost string) *circuitBreaker {
+	key := hostPort(serverHost)
+	b := s.breakers[key]
+	if b == nil {
+		b = &circuitBreaker{host: serverHost}
+		if s.breakers == nil {
+			s.breakers = make(map[string]*circuitBreaker)
+		}
+		s.breakers[key] = b
+	}
+	return b
+}
+
+// Allow 判断是否可以请求节点，熔断器关闭时按 isDown 判断
+func (b *circuitBreaker) Allow(isDown bool) bool {
+	_, timeout := getCircuitBreaker()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := time.Now()
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < timeout {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.probeAt = now
+		return true
+	case StateHalfOpen:
+		// 探测请求没有反馈结果时，超时后允许再次探测
+		if !b.probeAt.IsZero() && now.Sub(b.probeAt) < timeout {
+			return false
+		}
+		b.probeAt = now
+		return true
+	}
+	return !isDown
+}
+
+// onFailure 记录节点请求失败，返回节点是否熔断
+func (b *circuitBreaker) onFailure() bool {
+	threshold, _ := getCircuitBreaker()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= threshold {
+		if b.state != StateOpen {
+			extension.GetMetrics().IncNodeDown(b.host)
+		}
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		b.probeAt = time.Time{}
+	}
+	return b.state == StateOpen
+}
+
+// onSuccess 记录节点请求成功，关闭熔断器
+func (b *circuitBreaker) onSuccess() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.state = StateClosed
+	b.failures = 0
+	b.probeAt = time.Time{}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+func setTestCircuitBreaker(t *testing.T, threshold int, timeout time.Duration) {
+	oldThreshold, oldTimeout := failureThreshold, openTimeout
+	SetCircuitBreaker(threshold, timeout)
+	t.Cleanup(func() {
+		SetCircuitBreaker(oldThreshold, oldTimeout)
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	setTestCircuitBreaker(t, 2, 50*time.Millisecond)
+	configService := "breaker"
+	node := &config.ServerInfo{HomepageURL: "http://10.0.0.1:8080/"}
+	SetServers(configService, map[string]*config.ServerInfo{node.HomepageURL: node})
+
+	Assert(t, AllowRequest(node), Equal(true))
+	Assert(t, GetNodeState(node.HomepageURL), Equal(StateClosed))
+
+	// 未达到失败阈值
+	SetDownNode(configService, node.HomepageURL)
+	Assert(t, node.IsDown, Equal(false))
+	Assert(t, AllowRequest(node), Equal(true))
+
+	SetDownNode(configService, node.HomepageURL)
+	Assert(t, node.IsDown, Equal(true))
+	Assert(t, GetNodeState(node.HomepageURL), Equal(StateOpen))
+	Assert(t, AllowRequest(node), Equal(false))
+
+	// 熔断超时后只放行一个探测请求
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, AllowRequest(node), Equal(true))
+	Assert(t, GetNodeState(node.HomepageURL), Equal(StateHalfOpen))
+	Assert(t, AllowRequest(node), Equal(false))
+
+	// 探测失败重新熔断
+	SetDownNode(configService, node.HomepageURL)
+	Assert(t, GetNodeState(node.HomepageURL), Equal(StateOpen))
+	Assert(t, AllowRequest(node), Equal(false))
+
+	// 探测成功恢复
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, AllowRequest(node), Equal(true))
+	SetUpNode(configService, node.HomepageURL)
+	Assert(t, GetNodeState(node.HomepageURL), Equal(StateClosed))
+	Assert(t, node.IsDown, Equal(false))
+	Assert(t, AllowRequest(node), Equal(true))
+}
+
+func TestCircuitBreakerProbeTimeout(t *testing.T) {
+	setTestCircuitBreaker(t, 1, 50*time.Millisecond)
+	configService := "breakerProbe"
+	node := &config.ServerInfo{HomepageURL: "http://10.0.0.2:8080/"}
+	SetServers(configService, map[string]*config.ServerInfo{node.HomepageURL: node})
+
+	SetDownNode(configService, node.HomepageURL)
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, AllowRequest(node), Equal(true))
+	Assert(t, AllowRequest(node), Equal(false))
+
+	// 探测请求没有反馈结果，超时后允许再次探测
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, AllowRequest(node), Equal(true))
+}
+
+func TestHostCircuitBreaker(t *testing.T) {
+	setTestCircuitBreaker(t, 1, 50*time.Millisecond)
+	host := "http://meta.breaker:8080/"
+	Assert(t, AllowHost(host), Equal(true))
+
+	SetDownHost(host)
+	Assert(t, GetNodeState(host), Equal(StateOpen))
+	Assert(t, AllowHost(host), Equal(false))
+
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, AllowHost(host), Equal(true))
+	SetUpHost(host)
+	Assert(t, GetNodeState(host), Equal(StateClosed))
+	Assert(t, AllowHost(host), Equal(true))
+}
+
+func TestAllowRequestWithoutBreaker(t *testing.T) {
+	Assert(t, AllowRequest(nil), Equal(false))
+	Assert(t, AllowRequest(&config.ServerInfo{HomepageURL: "http://10.0.0.3:8080/"}), Equal(true))
+	Assert(t, AllowRequest(&config.ServerInfo{HomepageURL: "http://10.0.0.3:8080/", IsDown: true}), Equal(false))
+	Assert(t, StateHalfOpen.String(), Equal("half-open"))
+}
+
+func TestCircuitBreakerPerServers(t *testing.T) {
+	setTestCircuitBreaker(t, 1, time.Minute)
+	configService := "http://meta.breaker:8080/"
+	node := &config.ServerInfo{HomepageURL: "http://10.0.0.4:8080/"}
+	otherNode := &config.ServerInfo{HomepageURL: "http://10.0.0.4:8080/"}
+	servers := NewServers()
+	other := NewServers()
+	servers.SetServers(configService, map[string]*config.ServerInfo{node.HomepageURL: node})
+	other.SetServers(configService, map[string]*config.ServerInfo{otherNode.HomepageURL: otherNode})
+
+	servers.SetDownNode(configService, node.HomepageURL)
+	Assert(t, AllowRequest(node), Equal(false))
+	Assert(t, AllowRequest(otherNode), Equal(true))
+	Assert(t, servers.GetNodeState(node.HomepageURL), Equal(StateOpen))
+	Assert(t, other.GetNodeState(node.HomepageURL), Equal(StateClosed))
+	Assert(t, GetNodeState(node.HomepageURL), Equal(StateClosed))
+
+	// 刷新节点列表后保留熔断状态
+	refreshed := &config.ServerInfo{HomepageURL: node.HomepageURL}
+	servers.SetServers(configService, map[string]*config.ServerInfo{refreshed.HomepageURL: refreshed})
+	Assert(t, AllowRequest(refreshed), Equal(false))
+
+	servers.SetDownHost(configService)
+	Assert(t, servers.AllowHost(configService), Equal(false))
+	Assert(t, other.AllowHost(configService), Equal(true))
+}
+
+func TestSetDownNodeExactMatch(t *testing.T) {
+	setTestCircuitBreaker(t, 1, time.Minute)
+	configService := "http://meta.exact:8080/"
+	node := &config.ServerInfo{HomepageURL: "http://10.0.0.1:8080/"}
+	similar := &config.ServerInfo{HomepageURL: "http://10.0.0.11:8080/"}
+	servers := NewServers()
+	servers.SetServers(configService, map[string]*config.ServerInfo{
+		node.HomepageURL:    node,
+		similar.HomepageURL: similar,
+	})
+
+	servers.SetDownNode(configService, "10.0.0.1:8080")
+	Assert(t, node.IsDown, Equal(true))
+	Assert(t, similar.IsDown, Equal(false))
+
+	servers.SetDownNode(configService, "http://10.0.0.11:8080")
+	Assert(t, similar.IsDown, Equal(true))
+	servers.SetUpNode(configService, "10.0.0.1:8080")
+	Assert(t, node.IsDown, Equal(false))
+	Assert(t, similar.IsDown, Equal(true))
+}
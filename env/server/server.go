@@ -24,14 +24,16 @@ import (
 
 // ip -> server
 var (
-	ipMap      map[string]*Info
-	serverLock sync.Mutex
+	ipMap map[string]*Info
+	// defaultServers 全局节点列表，使用 ipMap 保存
+	defaultServers *Servers
 	//next try connect period - 60 second
 	nextTryConnectPeriod int64 = 30
 )
 
 func init() {
 	ipMap = make(map[string]*Info)
+	defaultServers = &Servers{ipMap: ipMap}
 }
 
 type Info struct {
@@ -40,79 +42,200 @@ type Info struct {
 	nextTryConnTime int64
 }
 
-// GetServersLen 获取服务器数组
+// Servers config service 节点列表，key 为 meta server 地址，
+// 每个客户端可以使用独立的节点列表，节点的熔断器保存在所属的节点列表中，不在客户端之间共享
+type Servers struct {
+	ipMap map[string]*Info
+	lock  sync.Mutex
+	// breakers 节点 host:port -> 熔断器
+	breakers map[string]*circuitBreaker
+	// nextTryConnectPeriod 节点失效后重新尝试直连 meta server 的间隔（秒），为 0 时使用默认值
+	nextTryConnectPeriod int64
+}
+
+// NewServers 创建空的节点列表
+func NewServers() *Servers {
+	return &Servers{
+		ipMap: make(map[string]*Info),
+	}
+}
+
+// GetDefaultServers 获取全局节点列表，包级别的函数均操作该列表
+func GetDefaultServers() *Servers {
+	return defaultServers
+}
+
+// GetServers 获取服务器数组
 func GetServers(configIp string) map[string]*config.ServerInfo {
-	serverLock.Lock()
-	defer serverLock.Unlock()
-	if ipMap[configIp] == nil {
+	return defaultServers.GetServers(configIp)
+}
+
+// GetServersLen 获取服务器数组长度
+func GetServersLen(configIp string) int {
+	return defaultServers.GetServersLen(configIp)
+}
+
+// SetServers 设置服务器数组
+func SetServers(configIp string, serverMap map[string]*config.ServerInfo) {
+	defaultServers.SetServers(configIp, serverMap)
+}
+
+// SetDownNode 设置失效节点
+func SetDownNode(configService string, serverHost string) {
+	defaultServers.SetDownNode(configService, serverHost)
+}
+
+// SetUpNode 设置恢复节点，关闭节点的熔断器
+func SetUpNode(configService string, serverHost string) {
+	defaultServers.SetUpNode(configService, serverHost)
+}
+
+// IsConnectDirectly is connect by ip directly
+// false : yes
+// true : no
+func IsConnectDirectly(configIp string) bool {
+	return defaultServers.IsConnectDirectly(configIp)
+}
+
+// SetNextTryConnTime if this connect is fail will set this time
+func SetNextTryConnTime(configIp string, nextPeriod int64) {
+	defaultServers.SetNextTryConnTime(configIp, nextPeriod)
+}
+
+// SetNextTryConnectPeriod 设置节点失效后重新尝试直连 meta server 的间隔，按秒取整
+func (s *Servers) SetNextTryConnectPeriod(period time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.nextTryConnectPeriod = int64(period / time.Second)
+}
+
+func (s *Servers) getNextTryConnectPeriod() int64 {
+	if s.nextTryConnectPeriod > 0 {
+		return s.nextTryConnectPeriod
+	}
+	return nextTryConnectPeriod
+}
+
+// GetServers 获取服务器数组
+func (s *Servers) GetServers(configIp string) map[string]*config.ServerInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ipMap[configIp] == nil {
 		return nil
 	}
-	return ipMap[configIp].serverMap
+	return s.ipMap[configIp].serverMap
 }
 
 // GetServersLen 获取服务器数组长度
-func GetServersLen(configIp string) int {
-	serverLock.Lock()
-	defer serverLock.Unlock()
-	s := ipMap[configIp]
-	if s == nil || len(s.serverMap) == 0 {
+func (s *Servers) GetServersLen(configIp string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.ipMap[configIp]
+	if info == nil || len(info.serverMap) == 0 {
 		return 0
 	}
-	return len(s.serverMap)
+	return len(info.serverMap)
 }
 
-func SetServers(configIp string, serverMap map[string]*config.ServerInfo) {
-	serverLock.Lock()
-	defer serverLock.Unlock()
-	ipMap[configIp] = &Info{
+// SetServers 设置服务器数组
+func (s *Servers) SetServers(configIp string, serverMap map[string]*config.ServerInfo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, server := range serverMap {
+		if server == nil {
+			continue
+		}
+		// 刷新节点列表后保留节点的熔断状态
+		server.Breaker = s.getBreaker(server.HomepageURL)
+	}
+	s.ipMap[configIp] = &Info{
 		serverMap: serverMap,
 	}
 }
 
 // SetDownNode 设置失效节点
-func SetDownNode(configService string, serverHost string) {
-	serverLock.Lock()
-	defer serverLock.Unlock()
-	s := ipMap[configService]
+func (s *Servers) SetDownNode(configService string, serverHost string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.ipMap[configService]
 	if serverHost == "" {
 		return
 	}
 
-	if s == nil || len(s.serverMap) == 0 {
+	if info == nil || len(info.serverMap) == 0 {
 		// init server map
-		ipMap[configService] = &Info{
+		s.ipMap[configService] = &Info{
 			serverMap: map[string]*config.ServerInfo{
 				serverHost: {
 					HomepageURL: serverHost,
+					Breaker:     s.getBreaker(serverHost),
 				},
 			},
 		}
-		s = ipMap[configService]
+		info = s.ipMap[configService]
 	}
 
 	if serverHost == configService {
-		s.nextTryConnTime = time.Now().Unix() + nextTryConnectPeriod
+		info.nextTryConnTime = time.Now().Unix() + s.getNextTryConnectPeriod()
 	}
 
-	for k, server := range s.serverMap {
+	for k, server := range info.serverMap {
 		// if some node has down then select next node
-		if strings.Contains(k, serverHost) {
-			server.IsDown = true
+		if isSameHost(k, server, serverHost) {
+			server.IsDown = s.getBreaker(server.HomepageURL).onFailure()
 		}
 	}
 }
 
+// SetUpNode 设置恢复节点，关闭节点的熔断器
+func (s *Servers) SetUpNode(configService string, serverHost string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.ipMap[configService]
+	if serverHost == "" || info == nil {
+		return
+	}
+
+	for k, server := range info.serverMap {
+		if isSameHost(k, server, serverHost) {
+			s.getBreaker(server.HomepageURL).onSuccess()
+			server.IsDown = false
+		}
+	}
+}
+
+// isSameHost 按 host:port 精确判断节点与 serverHost 是否为同一节点，key 与 HomepageURL 任一匹配即可
+func isSameHost(key string, server *config.ServerInfo, serverHost string) bool {
+	host := hostPort(serverHost)
+	if hostPort(key) == host {
+		return true
+	}
+	return server != nil && hostPort(server.HomepageURL) == host
+}
+
+// hostPort 获取地址的 host:port，如 http://10.0.0.1:8080/ => 10.0.0.1:8080
+func hostPort(address string) string {
+	address = strings.TrimSpace(address)
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	if i := strings.IndexAny(address, "/?#"); i >= 0 {
+		address = address[:i]
+	}
+	return strings.ToLower(address)
+}
+
 // IsConnectDirectly is connect by ip directly
 // false : yes
 // true : no
-func IsConnectDirectly(configIp string) bool {
-	serverLock.Lock()
-	defer serverLock.Unlock()
-	s := ipMap[configIp]
-	if s == nil || len(s.serverMap) == 0 {
+func (s *Servers) IsConnectDirectly(configIp string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.ipMap[configIp]
+	if info == nil || len(info.serverMap) == 0 {
 		return false
 	}
-	if s.nextTryConnTime >= 0 && s.nextTryConnTime > time.Now().Unix() {
+	if info.nextTryConnTime >= 0 && info.nextTryConnTime > time.Now().Unix() {
 		return true
 	}
 
@@ -120,20 +243,20 @@ func IsConnectDirectly(configIp string) bool {
 }
 
 // SetNextTryConnTime if this connect is fail will set this time
-func SetNextTryConnTime(configIp string, nextPeriod int64) {
-	serverLock.Lock()
-	defer serverLock.Unlock()
-	s := ipMap[configIp]
-	if s == nil || len(s.serverMap) == 0 {
-		s = &Info{
+func (s *Servers) SetNextTryConnTime(configIp string, nextPeriod int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.ipMap[configIp]
+	if info == nil || len(info.serverMap) == 0 {
+		info = &Info{
 			serverMap:       nil,
 			nextTryConnTime: 0,
 		}
-		ipMap[configIp] = s
+		s.ipMap[configIp] = info
 	}
 	tmp := nextPeriod
 	if tmp == 0 {
-		tmp = nextTryConnectPeriod
+		tmp = s.getNextTryConnectPeriod()
 	}
-	s.nextTryConnTime = time.Now().Unix() + tmp
+	info.nextTryConnTime = time.Now().Unix() + tmp
 }
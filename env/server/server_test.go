@@ -52,3 +52,15 @@ func TestAppConfig_IsConnectDirectly(t *testing.T) {
 	isConnectDirectly = IsConnectDirectly(name)
 	Assert(t, isConnectDirectly, Equal(false))
 }
+
+func TestSetNextTryConnectPeriod(t *testing.T) {
+	s := NewServers()
+	Assert(t, s.getNextTryConnectPeriod(), Equal(nextTryConnectPeriod))
+
+	s.SetNextTryConnectPeriod(2 * time.Minute)
+	Assert(t, s.getNextTryConnectPeriod(), Equal(int64(120)))
+
+	now := time.Now().Unix()
+	s.SetNextTryConnTime(name, 0)
+	Assert(t, s.ipMap[name].nextTryConnTime >= now+120, Equal(true))
+}
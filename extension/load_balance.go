@@ -27,3 +27,15 @@ func SetLoadBalance(loadBalance cluster.LoadBalance) {
 func GetLoadBalance() cluster.LoadBalance {
 	return defaultLoadBalance
 }
+
+var namedLoadBalance = make(map[string]cluster.LoadBalance)
+
+// AddLoadBalance 按名称注册负载均衡器，可通过 AppConfig.LoadBalance 选择
+func AddLoadBalance(name string, loadBalance cluster.LoadBalance) {
+	namedLoadBalance[name] = loadBalance
+}
+
+// GetNamedLoadBalance 根据名称获取负载均衡器
+func GetNamedLoadBalance(name string) cluster.LoadBalance {
+	return namedLoadBalance[name]
+}
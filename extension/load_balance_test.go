@@ -38,3 +38,11 @@ func TestSetLoadBalance(t *testing.T) {
 	b := balance.(*TestLoadBalance)
 	Assert(t, b, NotNilVal())
 }
+
+func TestAddLoadBalance(t *testing.T) {
+	AddLoadBalance("test", &TestLoadBalance{})
+
+	b := GetNamedLoadBalance("test").(*TestLoadBalance)
+	Assert(t, b, NotNilVal())
+	Assert(t, GetNamedLoadBalance("notExist"), NilVal())
+}
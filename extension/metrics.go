@@ -0,0 +1,34 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension
+
+import (
+	"github.com/apolloconfig/agollo/v5/metrics"
+)
+
+var metricsCollector metrics.Metrics = &metrics.NoopMetrics{}
+
+// SetMetrics 设置指标收集器，设置为 nil 时不收集指标
+func SetMetrics(m metrics.Metrics) {
+	if m == nil {
+		m = &metrics.NoopMetrics{}
+	}
+	metricsCollector = m
+}
+
+// GetMetrics 获取指标收集器，未设置时返回不收集指标的 NoopMetrics
+func GetMetrics() metrics.Metrics {
+	return metricsCollector
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/metrics"
+)
+
+type TestMetrics struct {
+	metrics.NoopMetrics
+}
+
+func TestSetMetrics(t *testing.T) {
+	_, ok := GetMetrics().(*metrics.NoopMetrics)
+	Assert(t, ok, Equal(true))
+
+	SetMetrics(&TestMetrics{})
+	_, ok = GetMetrics().(*TestMetrics)
+	Assert(t, ok, Equal(true))
+
+	SetMetrics(nil)
+	_, ok = GetMetrics().(*metrics.NoopMetrics)
+	Assert(t, ok, Equal(true))
+}
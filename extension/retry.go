@@ -0,0 +1,31 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension
+
+import (
+	"github.com/apolloconfig/agollo/v5/protocol/retry"
+)
+
+var retryPolicy retry.RetryPolicy
+
+// SetRetryPolicy 设置请求 apollo 失败后的重试策略
+func SetRetryPolicy(policy retry.RetryPolicy) {
+	retryPolicy = policy
+}
+
+// GetRetryPolicy 获取重试策略，未设置时返回 nil
+func GetRetryPolicy() retry.RetryPolicy {
+	return retryPolicy
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+)
+
+type TestRetryPolicy struct {
+}
+
+// Retryable 是否可以重试
+func (r *TestRetryPolicy) Retryable(statusCode int) bool {
+	return false
+}
+
+// NextBackOff 重试等待时间
+func (r *TestRetryPolicy) NextBackOff(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	return 0, false
+}
+
+func TestSetRetryPolicy(t *testing.T) {
+	SetRetryPolicy(&TestRetryPolicy{})
+	defer SetRetryPolicy(nil)
+
+	policy := GetRetryPolicy()
+
+	p := policy.(*TestRetryPolicy)
+	Assert(t, p, NotNilVal())
+}
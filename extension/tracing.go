@@ -0,0 +1,34 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension
+
+import (
+	"github.com/apolloconfig/agollo/v5/tracing"
+)
+
+var tracer tracing.Tracer = &tracing.NoopTracer{}
+
+// SetTracer 设置链路追踪，设置为 nil 时不追踪
+func SetTracer(t tracing.Tracer) {
+	if t == nil {
+		t = &tracing.NoopTracer{}
+	}
+	tracer = t
+}
+
+// GetTracer 获取链路追踪，未设置时返回不追踪的 NoopTracer
+func GetTracer() tracing.Tracer {
+	return tracer
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/tracing"
+)
+
+type TestTracer struct {
+	tracing.NoopTracer
+}
+
+func TestSetTracer(t *testing.T) {
+	_, ok := GetTracer().(*tracing.NoopTracer)
+	Assert(t, ok, Equal(true))
+
+	SetTracer(&TestTracer{})
+	_, ok = GetTracer().(*TestTracer)
+	Assert(t, ok, Equal(true))
+
+	SetTracer(nil)
+	_, ok = GetTracer().(*tracing.NoopTracer)
+	Assert(t, ok, Equal(true))
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "time"
+
+// Metrics 指标收集接口，会被多个 goroutine 同时调用，实现需要保证并发安全且不能阻塞
+type Metrics interface {
+	// ObserveRequest 记录一次请求 apollo 的耗时，api 为请求的接口（如 notifications、configs），
+	// statusCode 为 0 表示网络错误
+	ObserveRequest(api string, statusCode int, cost time.Duration)
+	// IncRetry 记录一次请求失败后的重试
+	IncRetry(api string)
+	// IncNodeDown 记录一次节点熔断
+	IncNodeDown(host string)
+	// IncSyncFailure 记录一次 namespace 同步失败
+	IncSyncFailure(namespace string)
+	// IncBackupFallback 记录一次从备份文件加载 namespace 配置
+	IncBackupFallback(namespace string)
+	// IncCacheUpdate 记录一次 namespace 缓存变更
+	IncCacheUpdate(namespace string)
+	// SetReleaseKey 记录 namespace 当前的 release key
+	SetReleaseKey(namespace string, releaseKey string)
+	// ObserveListenerDispatch 记录一次变更监听器的处理耗时
+	ObserveListenerDispatch(namespace string, cost time.Duration)
+}
+
+// NoopMetrics 不收集任何指标，未设置 Metrics 时使用
+type NoopMetrics struct {
+}
+
+// ObserveRequest 不收集
+func (*NoopMetrics) ObserveRequest(api string, statusCode int, cost time.Duration) {
+}
+
+// IncRetry 不收集
+func (*NoopMetrics) IncRetry(api string) {
+}
+
+// IncNodeDown 不收集
+func (*NoopMetrics) IncNodeDown(host string) {
+}
+
+// IncSyncFailure 不收集
+func (*NoopMetrics) IncSyncFailure(namespace string) {
+}
+
+// IncBackupFallback 不收集
+func (*NoopMetrics) IncBackupFallback(namespace string) {
+}
+
+// IncCacheUpdate 不收集
+func (*NoopMetrics) IncCacheUpdate(namespace string) {
+}
+
+// SetReleaseKey 不收集
+func (*NoopMetrics) SetReleaseKey(namespace string, releaseKey string) {
+}
+
+// ObserveListenerDispatch 不收集
+func (*NoopMetrics) ObserveListenerDispatch(namespace string, cost time.Duration) {
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "agollo"
+	// statusError 网络错误时 status 标签的值
+	statusError = "error"
+)
+
+// Metrics 基于 prometheus 的指标收集器，通过 extension.SetMetrics 设置后生效
+type Metrics struct {
+	requestDuration          *prometheus.HistogramVec
+	retries                  *prometheus.CounterVec
+	nodeDowns                *prometheus.CounterVec
+	syncFailures             *prometheus.CounterVec
+	backupFallbacks          *prometheus.CounterVec
+	cacheUpdates             *prometheus.CounterVec
+	releaseKey               *prometheus.GaugeVec
+	listenerDispatchDuration *prometheus.HistogramVec
+
+	// releaseKeys 记录每个 namespace 当前的 release key，更新时删除旧的指标
+	releaseKeys map[string]string
+	lock        sync.Mutex
+}
+
+// NewMetrics 创建指标收集器并注册到 registerer，registerer 为 nil 时注册到 prometheus.DefaultRegisterer
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	m := &Metrics{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of requests to apollo server by api and status.",
+			Buckets:   []float64{.01, .05, .1, .5, 1, 5, 30, 60, 90},
+		}, []string{"api", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_retries_total",
+			Help:      "Total number of retried requests to apollo server.",
+		}, []string{"api"}),
+		nodeDowns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "node_down_total",
+			Help:      "Total number of times a server node was marked down.",
+		}, []string{"host"}),
+		syncFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "sync_failures_total",
+			Help:      "Total number of failed namespace syncs.",
+		}, []string{"namespace"}),
+		backupFallbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "backup_fallbacks_total",
+			Help:      "Total number of namespaces loaded from backup files.",
+		}, []string{"namespace"}),
+		cacheUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "cache_updates_total",
+			Help:      "Total number of namespace cache changes.",
+		}, []string{"namespace"}),
+		releaseKey: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "release_key_info",
+			Help:      "Current release key of each namespace.",
+		}, []string{"namespace", "release_key"}),
+		listenerDispatchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "listener_dispatch_duration_seconds",
+			Help:      "Duration of change listener callbacks.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"namespace"}),
+		releaseKeys: make(map[string]string),
+	}
+
+	collectors := []prometheus.Collector{
+		m.requestDuration,
+		m.retries,
+		m.nodeDowns,
+		m.syncFailures,
+		m.backupFallbacks,
+		m.cacheUpdates,
+		m.releaseKey,
+		m.listenerDispatchDuration,
+	}
+	for _, c := range collectors {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest 记录请求耗时
+func (m *Metrics) ObserveRequest(api string, statusCode int, cost time.Duration) {
+	status := statusError
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	m.requestDuration.WithLabelValues(api, status).Observe(cost.Seconds())
+}
+
+// IncRetry 记录重试
+func (m *Metrics) IncRetry(api string) {
+	m.retries.WithLabelValues(api).Inc()
+}
+
+// IncNodeDown 记录节点熔断
+func (m *Metrics) IncNodeDown(host string) {
+	m.nodeDowns.WithLabelValues(host).Inc()
+}
+
+// IncSyncFailure 记录同步失败
+func (m *Metrics) IncSyncFailure(namespace string) {
+	m.syncFailures.WithLabelValues(namespace).Inc()
+}
+
+// IncBackupFallback 记录从备份文件加载配置
+func (m *Metrics) IncBackupFallback(namespace string) {
+	m.backupFallbacks.WithLabelValues(namespace).Inc()
+}
+
+// IncCacheUpdate 记录缓存变更
+func (m *Metrics) IncCacheUpdate(namespace string) {
+	m.cacheUpdates.WithLabelValues(namespace).Inc()
+}
+
+// SetReleaseKey 记录 namespace 当前的 release key
+func (m *Metrics) SetReleaseKey(namespace string, releaseKey string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if old, ok := m.releaseKeys[namespace]; ok {
+		if old == releaseKey {
+			return
+		}
+		m.releaseKey.DeleteLabelValues(namespace, old)
+	}
+	m.releaseKeys[namespace] = releaseKey
+	m.releaseKey.WithLabelValues(namespace, releaseKey).Set(1)
+}
+
+// ObserveListenerDispatch 记录变更监听器的处理耗时
+func (m *Metrics) ObserveListenerDispatch(namespace string, cost time.Duration) {
+	m.listenerDispatchDuration.WithLabelValues(namespace).Observe(cost.Seconds())
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/metrics"
+)
+
+var _ metrics.Metrics = (*Metrics)(nil)
+
+func TestNewMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := NewMetrics(registry)
+	Assert(t, err, NilVal())
+
+	m.ObserveRequest("notifications", 200, time.Second)
+	m.ObserveRequest("notifications", 0, time.Second)
+	m.IncRetry("configs")
+	m.IncNodeDown("http://10.0.0.1:8080/")
+	m.IncSyncFailure("application")
+	m.IncBackupFallback("application")
+	m.IncCacheUpdate("application")
+	m.ObserveListenerDispatch("application", time.Millisecond)
+
+	Assert(t, testutil.CollectAndCount(m.requestDuration), Equal(2))
+	Assert(t, testutil.ToFloat64(m.retries.WithLabelValues("configs")), Equal(float64(1)))
+	Assert(t, testutil.ToFloat64(m.nodeDowns.WithLabelValues("http://10.0.0.1:8080/")), Equal(float64(1)))
+	Assert(t, testutil.ToFloat64(m.syncFailures.WithLabelValues("application")), Equal(float64(1)))
+	Assert(t, testutil.ToFloat64(m.backupFallbacks.WithLabelValues("application")), Equal(float64(1)))
+	Assert(t, testutil.ToFloat64(m.cacheUpdates.WithLabelValues("application")), Equal(float64(1)))
+	Assert(t, testutil.CollectAndCount(m.listenerDispatchDuration), Equal(1))
+
+	// 重复注册
+	_, err = NewMetrics(registry)
+	Assert(t, err, NotNilVal())
+}
+
+func TestSetReleaseKey(t *testing.T) {
+	m, err := NewMetrics(prometheus.NewRegistry())
+	Assert(t, err, NilVal())
+
+	m.SetReleaseKey("application", "1")
+	m.SetReleaseKey("application", "1")
+	Assert(t, testutil.CollectAndCount(m.releaseKey), Equal(1))
+
+	m.SetReleaseKey("application", "2")
+	m.SetReleaseKey("other", "3")
+	Assert(t, testutil.CollectAndCount(m.releaseKey), Equal(2))
+	Assert(t, testutil.ToFloat64(m.releaseKey.WithLabelValues("application", "2")), Equal(float64(1)))
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agollo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/agcache"
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/component/log"
+	"github.com/apolloconfig/agollo/v5/constant"
+	"github.com/apolloconfig/agollo/v5/env"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/file"
+	"github.com/apolloconfig/agollo/v5/protocol/auth"
+	http2 "github.com/apolloconfig/agollo/v5/protocol/http"
+	"github.com/apolloconfig/agollo/v5/storage"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+)
+
+// Option 创建客户端的配置项
+type Option func(*options)
+
+type options struct {
+	loadAppConfig         func() (*config.AppConfig, error)
+	logger                log.StructuredLogger
+	cacheFactory          agcache.CacheFactory
+	transport             http.RoundTripper
+	extensions            env.Extensions
+	listeners             []storage.ChangeListener
+	longPollInterval      time.Duration
+	refreshIPListInterval time.Duration
+	notifyConnectTimeout  time.Duration
+	nextTryConnectPeriod  time.Duration
+	refreshInterval       time.Duration
+}
+
+// WithAppConfig 使用指定的 apollo 配置
+func WithAppConfig(appConfig *config.AppConfig) Option {
+	return func(o *options) {
+		o.loadAppConfig = func() (*config.AppConfig, error) {
+			return appConfig, nil
+		}
+	}
+}
+
+// WithAppConfigLoader 使用 loadAppConfig 加载 apollo 配置，未设置时读取默认配置文件
+func WithAppConfigLoader(loadAppConfig func() (*config.AppConfig, error)) Option {
+	return func(o *options) {
+		o.loadAppConfig = loadAppConfig
+	}
+}
+
+// WithLogger 设置当前客户端使用的结构化日志
+func WithLogger(logger log.StructuredLogger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithCacheFactory 设置当前客户端创建配置缓存使用的工厂
+func WithCacheFactory(cacheFactory agcache.CacheFactory) Option {
+	return func(o *options) {
+		o.cacheFactory = cacheFactory
+	}
+}
+
+// WithHTTPClient 使用 client 的 Transport 请求 apollo，超时仍由 AppConfig 中的配置控制
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		if client == nil {
+			return
+		}
+		o.transport = client.Transport
+		if o.transport == nil {
+			o.transport = http.DefaultTransport
+		}
+	}
+}
+
+// WithExtensions 设置当前客户端独占的组件，会覆盖之前设置的文件处理、负载均衡、授权与解析器
+func WithExtensions(extensions *env.Extensions) Option {
+	return func(o *options) {
+		if extensions != nil {
+			o.extensions = *extensions
+		}
+	}
+}
+
+// WithFormatParser 设置当前客户端指定格式 namespace 的内容解析器
+func WithFormatParser(format constant.ConfigFileFormat, parser parse.ContentParser) Option {
+	return func(o *options) {
+		// 复制后再修改，避免影响 WithExtensions 传入的 map
+		parsers := make(map[constant.ConfigFileFormat]parse.ContentParser, len(o.extensions.FormatParsers)+1)
+		for k, v := range o.extensions.FormatParsers {
+			parsers[k] = v
+		}
+		parsers[format] = parser
+		o.extensions.FormatParsers = parsers
+	}
+}
+
+// WithBackupFileHandler 设置当前客户端的备份文件处理组件
+func WithBackupFileHandler(fileHandler file.FileHandler) Option {
+	return func(o *options) {
+		o.extensions.FileHandler = fileHandler
+	}
+}
+
+// WithLoadBalance 设置当前客户端的负载均衡组件
+func WithLoadBalance(loadBalance cluster.LoadBalance) Option {
+	return func(o *options) {
+		o.extensions.LoadBalance = loadBalance
+	}
+}
+
+// WithSignature 设置当前客户端的 http 授权组件
+func WithSignature(auth auth.HTTPAuth) Option {
+	return func(o *options) {
+		o.extensions.HTTPAuth = auth
+	}
+}
+
+// WithChangeListener 添加配置变更监听器，启动时首次同步的变更也会通知该监听器
+func WithChangeListener(listener storage.ChangeListener) Option {
+	return func(o *options) {
+		if listener != nil {
+			o.listeners = append(o.listeners, listener)
+		}
+	}
+}
+
+// WithLongPollInterval 设置长轮询的间隔
+func WithLongPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.longPollInterval = interval
+	}
+}
+
+// WithRefreshIPListInterval 设置刷新 config service 节点列表的间隔
+func WithRefreshIPListInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.refreshIPListInterval = interval
+	}
+}
+
+// WithNotifyConnectTimeout 设置长轮询与同步配置的请求超时时间
+func WithNotifyConnectTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.notifyConnectTimeout = timeout
+	}
+}
+
+// WithNextTryConnectPeriod 设置节点失效后重新尝试直连 meta server 的间隔
+func WithNextTryConnectPeriod(period time.Duration) Option {
+	return func(o *options) {
+		o.nextTryConnectPeriod = period
+	}
+}
+
+// WithRefreshInterval 设置定期对账所有 namespace 的间隔，用于修复丢失的变更通知，负数表示不开启定期对账
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.refreshInterval = interval
+	}
+}
+
+// New 根据 opts 创建未启动的客户端，调用 Start 后开始同步配置；
+// opts 设置的组件只对该客户端生效，不修改 extension 包中的全局组件，未设置的组件使用全局组件
+func New(opts ...Option) (Client, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	loaded, err := env.InitConfig(o.loadAppConfig)
+	if err != nil {
+		return nil, err
+	}
+	if loaded == nil {
+		return nil, errors.New("app config can not be nil")
+	}
+	// 复制配置，opts 与客户端运行时的修改不影响调用方传入的配置
+	copied := *loaded
+	appConfig := &copied
+	if o.logger != nil {
+		appConfig.Logger = o.logger
+	}
+	if o.transport != nil {
+		appConfig.Transport = o.transport
+	}
+	if o.longPollInterval != 0 {
+		appConfig.LongPollInterval = config.Duration(o.longPollInterval)
+	}
+	if o.refreshIPListInterval != 0 {
+		appConfig.RefreshIPListInterval = config.Duration(o.refreshIPListInterval)
+	}
+	if o.notifyConnectTimeout != 0 {
+		appConfig.NotifyConnectTimeout = config.Duration(o.notifyConnectTimeout)
+	}
+	if o.nextTryConnectPeriod != 0 {
+		appConfig.NextTryConnectPeriod = config.Duration(o.nextTryConnectPeriod)
+	}
+	if o.refreshInterval != 0 {
+		appConfig.RefreshInterval = config.Duration(o.refreshInterval)
+	}
+	if err = appConfig.ValidateIntervals(); err != nil {
+		return nil, err
+	}
+	if err = http2.ValidateTLSConfig(appConfig.TLS); err != nil {
+		return nil, err
+	}
+
+	// 按设置后的 Logger 与 LogLevel 重新创建日志
+	appConfig.Init()
+
+	if fileHandler, ok := o.extensions.GetFileHandler().(file.ClientFileHandler); ok {
+		o.extensions.FileHandler = fileHandler.WithClient(file.ClientOptions{
+			Logger:       appConfig.GetLogger(),
+			Cluster:      appConfig.Cluster,
+			FormatParser: o.extensions.GetFormatParser,
+		})
+	}
+
+	c := newClient(appConfig, &o.extensions)
+	c.cache = storage.CreateNamespaceConfigWithFactory(appConfig.NamespaceName, o.cacheFactory)
+	c.cache.SetFileHandler(c.extensions.FileHandler)
+	c.cache.SetLogger(appConfig.GetLogger())
+	for _, listener := range o.listeners {
+		c.cache.AddChangeListener(listener)
+	}
+	return c, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agollo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/agcache"
+	"github.com/apolloconfig/agollo/v5/agcache/memory"
+	"github.com/apolloconfig/agollo/v5/constant"
+	"github.com/apolloconfig/agollo/v5/env"
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/storage"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+	"github.com/apolloconfig/agollo/v5/utils/parse/properties"
+)
+
+type countCacheFactory struct {
+	count int
+}
+
+func (f *countCacheFactory) Create() agcache.CacheInterface {
+	f.count++
+	return (&memory.DefaultCacheFactory{}).Create()
+}
+
+type newestChangeListener struct {
+	events chan *storage.FullChangeEvent
+}
+
+func (l *newestChangeListener) OnChange(event *storage.ChangeEvent) {
+}
+
+func (l *newestChangeListener) OnNewestChange(event *storage.FullChangeEvent) {
+	l.events <- event
+}
+
+func TestNew(t *testing.T) {
+	cacheFactory := &countCacheFactory{}
+	logger := &testStructuredLogger{}
+	fileHandler := &testFileHandler{}
+	auth := &TestAuth{}
+	appConfig := &config.AppConfig{AppID: "new", Cluster: "dev", NamespaceName: "application,db"}
+	client, err := New(WithAppConfig(appConfig),
+		WithLogger(logger),
+		WithCacheFactory(cacheFactory),
+		WithHTTPClient(&http.Client{}),
+		WithBackupFileHandler(fileHandler),
+		WithSignature(auth),
+		WithLongPollInterval(time.Second),
+		WithRefreshIPListInterval(time.Minute))
+	Assert(t, err, NilVal())
+
+	c := client.(*internalClient)
+	Assert(t, c.started.Load(), Equal(false))
+	Assert(t, len(c.components), Equal(0))
+	Assert(t, cacheFactory.count, Equal(2))
+	Assert(t, c.appConfig.GetLogger(), Equal(logger))
+	Assert(t, c.appConfig.Transport, Equal(http.DefaultTransport))
+	Assert(t, c.appConfig.GetLongPollInterval(), Equal(time.Second))
+	Assert(t, c.appConfig.GetRefreshIPListInterval(), Equal(time.Minute))
+	Assert(t, c.extensions.GetFileHandler(), Equal(fileHandler))
+	Assert(t, c.extensions.GetHTTPAuth(), Equal(auth))
+	Assert(t, c.extensions.Servers, NotNilVal())
+	Assert(t, extension.GetHTTPAuth() == auth, Equal(false))
+
+	// 不修改调用方传入的配置
+	Assert(t, c.appConfig == appConfig, Equal(false))
+	Assert(t, appConfig.Logger, NilVal())
+	Assert(t, appConfig.Transport, NilVal())
+	Assert(t, appConfig.LongPollInterval, Equal(config.Duration(0)))
+	Assert(t, appConfig.GetNotificationsMap(), NilVal())
+}
+
+func TestNewWithNilAppConfig(t *testing.T) {
+	client, err := New(WithAppConfig(nil))
+	Assert(t, client, NilVal())
+	Assert(t, err, NotNilVal())
+}
+
+func TestNewWithInvalidTLS(t *testing.T) {
+	appConfig := getTestAppConfig()
+	appConfig.TLS = config.TLSConfig{CAFile: "not-exist-ca.pem"}
+	client, err := New(WithAppConfig(appConfig))
+	Assert(t, client, NilVal())
+	Assert(t, err, NotNilVal())
+}
+
+func TestWithFormatParser(t *testing.T) {
+	parser := &properties.Parser{}
+	ext := &env.Extensions{
+		FormatParsers: map[constant.ConfigFileFormat]parse.ContentParser{},
+	}
+	o := &options{}
+	WithExtensions(ext)(o)
+	WithFormatParser(constant.YML, parser)(o)
+	Assert(t, o.extensions.GetFormatParser(constant.YML), Equal(parser))
+	Assert(t, len(ext.FormatParsers), Equal(0))
+}
+
+func TestNewStart(t *testing.T) {
+	c := &config.AppConfig{AppID: "new-start", Cluster: "dev", NamespaceName: "application"}
+	server := runMockConfigFilesServer(map[string]func(http.ResponseWriter, *http.Request){
+		"application": onlyNormalConfigResponse,
+	}, nil, c)
+	defer server.Close()
+	c.IP = server.URL
+
+	listener := &newestChangeListener{events: make(chan *storage.FullChangeEvent, 1)}
+	client, err := New(WithAppConfig(c), WithChangeListener(listener))
+	Assert(t, err, NilVal())
+	Assert(t, client.Start(context.Background()), NilVal())
+	defer client.Close()
+
+	Assert(t, client.GetValue("key1"), Equal("value1"))
+	select {
+	case event := <-listener.events:
+		Assert(t, event.Changes["key1"], Equal("value1"))
+	case <-time.After(time.Second):
+		t.Fatal("listener added by option was not notified")
+	}
+	Assert(t, client.Start(context.Background()), NotNilVal())
+}
@@ -15,7 +15,7 @@
 package http
 
 import (
-	"crypto/tls"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -26,11 +26,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/apolloconfig/agollo/v5/cluster"
+	"github.com/apolloconfig/agollo/v5/cluster/zone"
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
-	"github.com/apolloconfig/agollo/v5/env/server"
 	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/protocol/auth"
+	"github.com/apolloconfig/agollo/v5/protocol/retry"
+	"github.com/apolloconfig/agollo/v5/protocol/retry/backoff"
+	"github.com/apolloconfig/agollo/v5/tracing"
 	"github.com/apolloconfig/agollo/v5/utils"
 )
 
@@ -49,30 +54,78 @@ var (
 	defaultTimeoutBySecond = 1 * time.Second
 	//defaultKeepAliveSecond defines the connection time
 	defaultKeepAliveSecond = 60 * time.Second
-	// once for single http.Transport
-	once sync.Once
-	// defaultTransport http.Transport
-	defaultTransport *http.Transport
+	// transports 按连接池配置缓存的 http.Transport，配置相同的客户端共用连接池
+	transports sync.Map
 )
 
-func getDefaultTransport(insecureSkipVerify bool) *http.Transport {
-	once.Do(func() {
-		defaultTransport = &http.Transport{
-			Proxy:               http.ProxyFromEnvironment,
-			MaxIdleConns:        defaultMaxConnsPerHost,
-			MaxIdleConnsPerHost: defaultMaxConnsPerHost,
-			DialContext: (&net.Dialer{
-				KeepAlive: defaultKeepAliveSecond,
-				Timeout:   defaultTimeoutBySecond,
-			}).DialContext,
-		}
-		if insecureSkipVerify {
-			defaultTransport.TLSClientConfig = &tls.Config{
-				InsecureSkipVerify: insecureSkipVerify,
-			}
-		}
-	})
-	return defaultTransport
+// 指标统计的 apollo 接口名
+const (
+	apiConfigs       = "configs"
+	apiConfigFiles   = "configfiles"
+	apiNotifications = "notifications"
+	apiServices      = "services"
+	apiOther         = "other"
+)
+
+// transportKey 缓存 http.Transport 的 key
+type transportKey struct {
+	http config.HTTPTransportConfig
+	tls  config.TLSConfig
+}
+
+func getDefaultTransport() *http.Transport {
+	t, _ := getTransport(config.HTTPTransportConfig{}, config.TLSConfig{})
+	return t
+}
+
+func getTransport(transportConfig config.HTTPTransportConfig, tlsConfig config.TLSConfig) (*http.Transport, error) {
+	key := transportKey{
+		http: transportConfig,
+		tls:  tlsConfig,
+	}
+	if t, ok := transports.Load(key); ok {
+		return t.(*http.Transport), nil
+	}
+	transport, err := newTransport(transportConfig, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	t, _ := transports.LoadOrStore(key, transport)
+	return t.(*http.Transport), nil
+}
+
+func newTransport(transportConfig config.HTTPTransportConfig, tlsConfig config.TLSConfig) (*http.Transport, error) {
+	clientTLSConfig, err := newTLSConfig(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIdleConns := defaultMaxConnsPerHost
+	if transportConfig.MaxIdleConns > 0 {
+		maxIdleConns = transportConfig.MaxIdleConns
+	}
+	maxIdleConnsPerHost := defaultMaxConnsPerHost
+	if transportConfig.MaxIdleConnsPerHost > 0 {
+		maxIdleConnsPerHost = transportConfig.MaxIdleConnsPerHost
+	}
+	keepAlive := defaultKeepAliveSecond
+	if transportConfig.KeepAlive > 0 {
+		keepAlive = time.Duration(transportConfig.KeepAlive) * time.Second
+	}
+
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		MaxConnsPerHost:     transportConfig.MaxConnsPerHost,
+		IdleConnTimeout:     time.Duration(transportConfig.IdleConnTimeout) * time.Second,
+		DisableKeepAlives:   transportConfig.DisableKeepAlives,
+		TLSClientConfig:     clientTLSConfig,
+		DialContext: (&net.Dialer{
+			KeepAlive: keepAlive,
+			Timeout:   defaultTimeoutBySecond,
+		}).DialContext,
+	}, nil
 }
 
 // CallBack 请求回调函数
@@ -85,6 +138,36 @@ type CallBack struct {
 
 // Request 建立网络请求
 func Request(requestURL string, connectionConfig *env.ConnectConfig, callBack *CallBack) (interface{}, error) {
+	return RequestContext(context.Background(), requestURL, connectionConfig, callBack)
+}
+
+// RequestContext 建立网络请求，ctx 取消或超时后立即停止请求与重试
+func RequestContext(ctx context.Context, requestURL string, connectionConfig *env.ConnectConfig, callBack *CallBack) (interface{}, error) {
+	return requestContext(ctx, requestURL, connectionConfig, callBack)
+}
+
+// requestContext 建立网络请求并记录 span，attrs 为额外的 span 属性
+func requestContext(ctx context.Context, requestURL string, connectionConfig *env.ConnectConfig, callBack *CallBack, attrs ...tracing.Attribute) (interface{}, error) {
+	api := requestAPI(requestURL)
+	attrs = append(attrs, tracing.String(tracing.URL, requestURL), tracing.String(tracing.API, api))
+	if connectionConfig != nil {
+		attrs = append(attrs, tracing.String(tracing.AppID, connectionConfig.AppID))
+	}
+	if callBack != nil && callBack.Namespace != utils.Empty {
+		attrs = append(attrs, tracing.String(tracing.Namespace, callBack.Namespace))
+	}
+	ctx, span := extension.GetTracer().Start(ctx, tracing.SpanRequest, attrs...)
+
+	response, err := doRequest(ctx, requestURL, api, connectionConfig, callBack, span)
+	if apolloConfig, ok := response.(*config.ApolloConfig); ok && apolloConfig != nil {
+		span.SetAttributes(tracing.String(tracing.Namespace, apolloConfig.NamespaceName),
+			tracing.String(tracing.ReleaseKey, apolloConfig.ReleaseKey))
+	}
+	span.End(err)
+	return response, err
+}
+
+func doRequest(ctx context.Context, requestURL string, api string, connectionConfig *env.ConnectConfig, callBack *CallBack, span tracing.Span) (interface{}, error) {
 	client := &http.Client{}
 	//如有设置自定义超时时间即使用
 	if connectionConfig != nil && connectionConfig.Timeout != 0 {
@@ -92,39 +175,41 @@ func Request(requestURL string, connectionConfig *env.ConnectConfig, callBack *C
 	} else {
 		client.Timeout = connectTimeout
 	}
+	logger := getLogger(connectionConfig)
 	var err error
-	u, err := url.Parse(requestURL)
+	_, err = url.Parse(requestURL)
 	if err != nil {
-		log.Errorf("request Apollo Server url: %q is invalid: %v", requestURL, err)
+		logger.Log(log.LevelError, "request Apollo Server url is invalid", log.KV("url", requestURL), log.KV("error", err))
 		return nil, err
 	}
-	var insecureSkipVerify bool
-	if strings.HasPrefix(u.Scheme, "https") {
-		insecureSkipVerify = true
+	if connectionConfig != nil && connectionConfig.Transport != nil {
+		client.Transport = connectionConfig.Transport
+	} else if connectionConfig != nil {
+		client.Transport, err = getTransport(connectionConfig.HTTPTransport, connectionConfig.TLS)
+		if err != nil {
+			logger.Log(log.LevelError, "create http transport fail", log.KV("url", requestURL), log.KV("error", err))
+			return nil, err
+		}
+	} else {
+		client.Transport = getDefaultTransport()
 	}
-	client.Transport = getDefaultTransport(insecureSkipVerify)
+	policy := getRetryPolicy()
+	metrics := extension.GetMetrics()
+	startTime := time.Now()
 	retry := 0
-	var retries = maxRetries
-	if connectionConfig != nil && !connectionConfig.IsRetry {
-		retries = 1
-	}
 	for {
-
 		retry++
 
-		if retry > retries {
-			break
-		}
 		var req *http.Request
-		req, err = http.NewRequest("GET", requestURL, nil)
+		req, err = http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 		if req == nil || err != nil {
-			log.Errorf("Generate connect Apollo request Fail, url:%s, error:%v", requestURL, err)
+			logger.Log(log.LevelError, "Generate connect Apollo request Fail", log.KV("url", requestURL), log.KV("error", err))
 			// if error then sleep
 			return nil, errors.New("generate connect Apollo request fail")
 		}
 
 		//增加header选项
-		httpAuth := extension.GetHTTPAuth()
+		httpAuth := getHTTPAuth(connectionConfig)
 		if httpAuth != nil {
 			headers := httpAuth.HTTPHeaders(requestURL, connectionConfig.AppID, connectionConfig.Secret)
 			if len(headers) > 0 {
@@ -136,91 +221,262 @@ func Request(requestURL string, connectionConfig *env.ConnectConfig, callBack *C
 			}
 		}
 
+		// statusCode 为 0 表示网络错误
+		statusCode := 0
 		var res *http.Response
+		requestTime := time.Now()
 		res, err = client.Do(req)
 		if res != nil {
 			defer res.Body.Close()
 		}
 
 		if res == nil || err != nil {
-			log.Errorf("Connect Apollo Server Fail, url:%s, error:%v", requestURL, err)
-			// if error then sleep
-			time.Sleep(onErrorRetryInterval)
-			continue
-		}
-
-		//not modified break
-		switch res.StatusCode {
-		case http.StatusOK:
-			var responseBody []byte
-			responseBody, err = io.ReadAll(res.Body)
-			if err != nil {
-				log.Errorf("Connect Apollo Server Fail, url: %s , error: %v", requestURL, err)
-				// if error then sleep
-				time.Sleep(onErrorRetryInterval)
-				continue
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
 			}
+			metrics.ObserveRequest(api, statusCode, time.Since(requestTime))
+			logger.Log(log.LevelError, "Connect Apollo Server Fail", log.KV("url", requestURL), log.KV("error", err))
+		} else {
+			metrics.ObserveRequest(api, res.StatusCode, time.Since(requestTime))
+			span.SetAttributes(tracing.Int(tracing.HTTPStatusCode, res.StatusCode))
+			//not modified break
+			switch res.StatusCode {
+			case http.StatusOK:
+				var responseBody []byte
+				responseBody, err = io.ReadAll(res.Body)
+				if err != nil {
+					logger.Log(log.LevelError, "Connect Apollo Server Fail", log.KV("url", requestURL), log.KV("error", err))
+					break
+				}
 
-			if callBack != nil && callBack.SuccessCallBack != nil {
-				return callBack.SuccessCallBack(responseBody, *callBack)
-			}
-			return nil, nil
-		case http.StatusNotModified:
-			log.Debugf("Config Not Modified, error: %v", err)
-			if callBack != nil && callBack.NotModifyCallBack != nil {
-				return nil, callBack.NotModifyCallBack()
+				if callBack != nil && callBack.SuccessCallBack != nil {
+					return callBack.SuccessCallBack(responseBody, *callBack)
+				}
+				return nil, nil
+			case http.StatusNotModified:
+				logger.Log(log.LevelDebug, "Config Not Modified", log.KV("url", requestURL))
+				if callBack != nil && callBack.NotModifyCallBack != nil {
+					return nil, callBack.NotModifyCallBack()
+				}
+				return nil, nil
+			case http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusMethodNotAllowed:
+				logger.Log(log.LevelError, "Connect Apollo Server Fail", log.KV("url", requestURL), log.KV("status", res.StatusCode))
+				return nil, errors.New(fmt.Sprintf("Connect Apollo Server Fail, StatusCode:%d", res.StatusCode))
+			default:
+				logger.Log(log.LevelError, "Connect Apollo Server Fail", log.KV("url", requestURL), log.KV("status", res.StatusCode))
+				statusCode = res.StatusCode
+				if !policy.Retryable(statusCode) {
+					return nil, errors.New(fmt.Sprintf("Connect Apollo Server Fail, StatusCode:%d", res.StatusCode))
+				}
 			}
-			return nil, nil
-		case http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusMethodNotAllowed:
-			log.Errorf("Connect Apollo Server Fail, url:%s, StatusCode:%d", requestURL, res.StatusCode)
-			return nil, errors.New(fmt.Sprintf("Connect Apollo Server Fail, StatusCode:%d", res.StatusCode))
-		default:
-			log.Errorf("Connect Apollo Server Fail, url:%s, StatusCode:%d", requestURL, res.StatusCode)
-			// if error then sleep
-			time.Sleep(onErrorRetryInterval)
-			continue
+		}
+
+		if connectionConfig != nil && !connectionConfig.IsRetry {
+			break
+		}
+		backOff, ok := policy.NextBackOff(retry, time.Since(startTime))
+		if !ok {
+			break
+		}
+		metrics.IncRetry(api)
+		// if error then sleep
+		if err = sleep(ctx, backOff); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Log(log.LevelError, "Over Max Retry Still Error", log.KV("url", requestURL), log.KV("retry", retry), log.KV("error", err))
+	return nil, errors.New("over Max Retry Still Error")
+}
+
+// getHTTPAuth 获取请求使用的授权组件，未设置 ConnectConfig.Extensions 时使用全局组件
+func getHTTPAuth(connectionConfig *env.ConnectConfig) auth.HTTPAuth {
+	if connectionConfig == nil {
+		return extension.GetHTTPAuth()
+	}
+	return connectionConfig.Extensions.GetHTTPAuth()
+}
+
+// getLogger 获取请求使用的日志，未设置 ConnectConfig.Logger 时使用全局日志
+func getLogger(connectionConfig *env.ConnectConfig) log.StructuredLogger {
+	if connectionConfig != nil && connectionConfig.Logger != nil {
+		return connectionConfig.Logger
+	}
+	return log.GetStructuredLogger()
+}
+
+// requestAPI 获取请求的 apollo 接口名，用于指标统计
+func requestAPI(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return apiOther
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		switch segment {
+		case apiConfigs, apiConfigFiles, apiNotifications, apiServices:
+			return segment
 		}
 	}
+	return apiOther
+}
 
-	log.Errorf("Over Max Retry Still Error, error: %v", err)
-	if retry > retries {
-		err = errors.New("over Max Retry Still Error")
+// getRetryPolicy 获取重试策略，未通过 extension.SetRetryPolicy 设置时使用 backoff.NewExponentialBackOff 的推荐配置，
+// 首次等待 onErrorRetryInterval、最多请求 maxRetries 次，每次获取时按当前的间隔创建
+func getRetryPolicy() retry.RetryPolicy {
+	if policy := extension.GetRetryPolicy(); policy != nil {
+		return policy
 	}
-	return nil, err
+	policy := backoff.NewExponentialBackOff()
+	policy.InitialInterval = onErrorRetryInterval
+	policy.MaxAttempts = maxRetries
+	return policy
 }
 
 // RequestRecovery 可以恢复的请求
 func RequestRecovery(appConfig config.AppConfig,
+	connectConfig *env.ConnectConfig,
+	callBack *CallBack) (interface{}, error) {
+	return RequestRecoveryContext(context.Background(), appConfig, connectConfig, callBack)
+}
+
+// RequestRecoveryContext 可以恢复的请求，ctx 取消或超时后不再切换节点
+func RequestRecoveryContext(ctx context.Context, appConfig config.AppConfig,
 	connectConfig *env.ConnectConfig,
 	callBack *CallBack) (interface{}, error) {
 	format := "%s%s"
 	var err error
 	var response interface{}
+	servers := connectConfig.Extensions.GetServers()
+	// 每个节点在一次调用中最多请求一次，避免熔断超时后节点转为半开状态被反复请求，导致全部节点不可用时无法返回
+	tried := make(map[string]bool)
 
 	for {
-		host := loadBalance(appConfig)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		host, observer := loadBalance(appConfig, connectConfig.Extensions, tried)
 		if host == "" {
 			return nil, err
 		}
+		tried[host] = true
+		if connectConfig.IsLongPoll {
+			observer = nil
+		}
 
 		requestURL := fmt.Sprintf(format, host, connectConfig.URI)
-		response, err = Request(requestURL, connectConfig, callBack)
+		if observer != nil {
+			observer.OnRequestStart(host)
+		}
+		startTime := time.Now()
+		response, err = requestContext(ctx, requestURL, connectConfig, callBack, tracing.String(tracing.Cluster, appConfig.Cluster))
+		if observer != nil {
+			observer.OnRequestDone(host, time.Since(startTime), err)
+		}
+		isMetaHost := isMetaHost(appConfig, host)
 		if err == nil {
+			if isMetaHost {
+				servers.SetUpHost(host)
+			}
+			servers.SetUpNode(appConfig.GetHost(), host)
 			return response, nil
 		}
 
-		server.SetDownNode(appConfig.GetHost(), host)
+		// 主动取消的请求不代表节点失效
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if isMetaHost {
+			// 熔断失败的 meta server，下次请求切换到其他 meta server，全部请求失败后从服务列表中选择节点
+			servers.SetDownHost(host)
+			continue
+		}
+		servers.SetDownNode(appConfig.GetHost(), host)
 	}
 }
 
-func loadBalance(appConfig config.AppConfig) string {
-	if !server.IsConnectDirectly(appConfig.GetHost()) {
-		return appConfig.GetHost()
+// sleep 等待 d 时间，ctx 结束时提前返回 ctx.Err()
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
-	serverInfo := extension.GetLoadBalance().Load(server.GetServers(appConfig.GetHost()))
+}
+
+// loadBalance 选择请求的节点，跳过 tried 中本次调用已经请求过的节点
+func loadBalance(appConfig config.AppConfig, extensions *env.Extensions, tried map[string]bool) (string, cluster.RequestObserver) {
+	servers := extensions.GetServers()
+	if !servers.IsConnectDirectly(appConfig.GetHost()) {
+		hosts := appConfig.GetHosts()
+		if len(hosts) <= 1 && !tried[appConfig.GetHost()] {
+			return appConfig.GetHost(), nil
+		}
+		// 配置了多个 meta server 时选择第一个可用的，都不可用时从服务列表中选择节点
+		for _, host := range hosts {
+			if !tried[host] && servers.AllowHost(host) {
+				return host, nil
+			}
+		}
+	}
+	lb := getLoadBalance(appConfig, extensions)
+	serverInfo := lb.Load(untriedServers(servers.GetServers(appConfig.GetHost()), tried))
 	if serverInfo == nil {
-		return utils.Empty
+		return utils.Empty, nil
+	}
+
+	observer, _ := lb.(cluster.RequestObserver)
+	return serverInfo.HomepageURL, observer
+}
+
+// untriedServers 过滤掉本次调用已经请求过的节点
+func untriedServers(servers map[string]*config.ServerInfo, tried map[string]bool) map[string]*config.ServerInfo {
+	if len(tried) == 0 {
+		return servers
+	}
+	m := make(map[string]*config.ServerInfo, len(servers))
+	for k, serverInfo := range servers {
+		if !tried[serverInfo.HomepageURL] {
+			m[k] = serverInfo
+		}
 	}
+	return m
+}
 
-	return serverInfo.HomepageURL
+// isMetaHost 判断 host 是否为配置了多个 meta server 时的其中一个
+func isMetaHost(appConfig config.AppConfig, host string) bool {
+	hosts := appConfig.GetHosts()
+	if len(hosts) <= 1 {
+		return false
+	}
+	for _, h := range hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+// getLoadBalance 获取客户端独占的负载均衡器，未设置时获取 appConfig.LoadBalance 指定的负载均衡器，
+// 未指定或不存在时使用默认负载均衡器，设置了可用区时优先选择相同可用区的节点
+func getLoadBalance(appConfig config.AppConfig, extensions *env.Extensions) cluster.LoadBalance {
+	lb := extension.GetLoadBalance()
+	if own := extensions.GetLoadBalance(); own != nil {
+		lb = own
+	} else if appConfig.LoadBalance != utils.Empty {
+		if named := extension.GetNamedLoadBalance(appConfig.LoadBalance); named != nil {
+			lb = named
+		} else {
+			appConfig.GetLogger().Log(log.LevelWarn, "load balance not found, use default load balance",
+				log.KV("loadBalance", appConfig.LoadBalance))
+		}
+	}
+
+	if z := appConfig.GetZone(); z != utils.Empty {
+		return &zone.LoadBalance{Zone: z, Next: lb}
+	}
+	return lb
 }
@@ -15,22 +15,33 @@
 package http
 
 import (
+	"context"
 	json2 "encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	. "github.com/tevid/gohamcrest"
 
+	"github.com/apolloconfig/agollo/v5/cluster"
 	"github.com/apolloconfig/agollo/v5/cluster/roundrobin"
+	"github.com/apolloconfig/agollo/v5/cluster/zone"
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	"github.com/apolloconfig/agollo/v5/env/config/json"
 	"github.com/apolloconfig/agollo/v5/env/server"
 	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/metrics"
+	"github.com/apolloconfig/agollo/v5/protocol/retry/backoff"
+	"github.com/apolloconfig/agollo/v5/tracing"
 	"github.com/apolloconfig/agollo/v5/utils"
 )
 
@@ -71,6 +82,9 @@ func TestHttpsRequestRecovery(t *testing.T) {
 	o, err := RequestRecovery(*appConfig, &env.ConnectConfig{
 		URI:     urlSuffix,
 		IsRetry: true,
+		TLS: config.TLSConfig{
+			CAFile: writeCertFile(t, server.Certificate()),
+		},
 	}, &CallBack{
 		SuccessCallBack: nil,
 	})
@@ -167,6 +181,448 @@ func testFailFastStatusCode(t *testing.T, status int) {
 	Assert(t, int64(0), Equal(duration))
 }
 
+func TestRequestRecoveryContextCanceled(t *testing.T) {
+	server := runStatusCodeResponse(http.StatusInternalServerError)
+	appConfig := getTestAppConfig()
+	appConfig.IP = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	_, err := RequestRecoveryContext(ctx, *appConfig, &env.ConnectConfig{
+		URI:     getConfigURLSuffix(appConfig, appConfig.NamespaceName),
+		IsRetry: true,
+	}, &CallBack{
+		SuccessCallBack: nil,
+	})
+
+	Assert(t, err, Equal(context.DeadlineExceeded))
+	Assert(t, time.Since(startTime) < onErrorRetryInterval, Equal(true))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequestWithCustomTransport(t *testing.T) {
+	var requestURL string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requestURL = req.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("ok")),
+			Request:    req,
+		}, nil
+	})
+
+	o, err := Request("http://apollo.invalid/configs", &env.ConnectConfig{
+		Transport: transport,
+	}, &CallBack{
+		SuccessCallBack: func(body []byte, _ CallBack) (interface{}, error) {
+			return string(body), nil
+		},
+	})
+
+	Assert(t, err, NilVal())
+	Assert(t, o, Equal("ok"))
+	Assert(t, requestURL, Equal("http://apollo.invalid/configs"))
+}
+
+func TestGetTransport(t *testing.T) {
+	Assert(t, getDefaultTransport() == getDefaultTransport(), Equal(true))
+	Assert(t, getDefaultTransport().MaxIdleConns, Equal(defaultMaxConnsPerHost))
+	Assert(t, getDefaultTransport().TLSClientConfig, NilVal())
+
+	transportConfig := config.HTTPTransportConfig{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 2,
+		MaxConnsPerHost:     4,
+		IdleConnTimeout:     30,
+		DisableKeepAlives:   true,
+	}
+	transport, err := getTransport(transportConfig, config.TLSConfig{})
+	Assert(t, err, NilVal())
+	same, _ := getTransport(transportConfig, config.TLSConfig{})
+	Assert(t, transport == same, Equal(true))
+	Assert(t, transport == getDefaultTransport(), Equal(false))
+	Assert(t, transport.MaxIdleConns, Equal(10))
+	Assert(t, transport.MaxIdleConnsPerHost, Equal(2))
+	Assert(t, transport.MaxConnsPerHost, Equal(4))
+	Assert(t, transport.IdleConnTimeout, Equal(30*time.Second))
+	Assert(t, transport.DisableKeepAlives, Equal(true))
+}
+
+func TestRequestWithRetryPolicy(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	extension.SetRetryPolicy(&backoff.ExponentialBackOff{
+		InitialInterval:        10 * time.Millisecond,
+		Multiplier:             2,
+		MaxAttempts:            5,
+		RetryableStatusClasses: []int{5},
+	})
+	defer extension.SetRetryPolicy(nil)
+
+	// 503 重试，403 不在可重试范围内立即返回
+	startTime := time.Now()
+	_, err := Request(server.URL, &env.ConnectConfig{IsRetry: true}, nil)
+	Assert(t, err, NotNilVal())
+	Assert(t, atomic.LoadInt32(&count), Equal(int32(3)))
+	Assert(t, time.Since(startTime) < onErrorRetryInterval, Equal(true))
+
+	// 不重试的请求只请求一次
+	atomic.StoreInt32(&count, 0)
+	_, err = Request(server.URL, &env.ConnectConfig{}, nil)
+	Assert(t, err, NotNilVal())
+	Assert(t, atomic.LoadInt32(&count), Equal(int32(1)))
+}
+
+func TestRequestOverMaxAttempts(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	extension.SetRetryPolicy(&backoff.ExponentialBackOff{
+		InitialInterval: time.Millisecond,
+		MaxAttempts:     3,
+	})
+	defer extension.SetRetryPolicy(nil)
+
+	_, err := Request(server.URL, &env.ConnectConfig{IsRetry: true}, nil)
+	Assert(t, err, NotNilVal())
+	Assert(t, atomic.LoadInt32(&count), Equal(int32(3)))
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	interval := onErrorRetryInterval
+	defer func() {
+		onErrorRetryInterval = interval
+	}()
+	onErrorRetryInterval = 100 * time.Millisecond
+
+	policy := getRetryPolicy()
+	jittered := false
+	for i := 0; i < 10; i++ {
+		backOff, ok := policy.NextBackOff(1, 0)
+		Assert(t, ok, Equal(true))
+		Assert(t, backOff >= 50*time.Millisecond && backOff <= 150*time.Millisecond, Equal(true))
+		jittered = jittered || backOff != onErrorRetryInterval
+	}
+	Assert(t, jittered, Equal(true))
+	backOff, ok := policy.NextBackOff(3, 0)
+	Assert(t, ok, Equal(true))
+	Assert(t, backOff >= 200*time.Millisecond && backOff <= 600*time.Millisecond, Equal(true))
+	_, ok = policy.NextBackOff(maxRetries, 0)
+	Assert(t, ok, Equal(false))
+	_, ok = policy.NextBackOff(1, time.Minute)
+	Assert(t, ok, Equal(false))
+	Assert(t, policy.Retryable(http.StatusServiceUnavailable), Equal(true))
+	Assert(t, policy.Retryable(http.StatusTooManyRequests), Equal(true))
+	Assert(t, policy.Retryable(http.StatusBadRequest), Equal(false))
+}
+
+type testObserverLoadBalance struct {
+	started []string
+	costs   []time.Duration
+}
+
+func (l *testObserverLoadBalance) Load(servers map[string]*config.ServerInfo) *config.ServerInfo {
+	for _, info := range servers {
+		return info
+	}
+	return nil
+}
+
+func (l *testObserverLoadBalance) OnRequestStart(homepageURL string) {
+	l.started = append(l.started, homepageURL)
+}
+
+func (l *testObserverLoadBalance) OnRequestDone(homepageURL string, cost time.Duration, err error) {
+	l.costs = append(l.costs, cost)
+}
+
+type testStructuredLogger struct {
+	msgs []string
+}
+
+func (l *testStructuredLogger) Enabled(level log.Level) bool {
+	return true
+}
+
+func (l *testStructuredLogger) Log(level log.Level, msg string, fields ...log.Field) {
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestGetLoadBalance(t *testing.T) {
+	appConfig := getTestAppConfig()
+	Assert(t, getLoadBalance(*appConfig, nil), Equal(extension.GetLoadBalance()))
+
+	lb := &testObserverLoadBalance{}
+	extension.AddLoadBalance("observer", lb)
+	appConfig.LoadBalance = "observer"
+	Assert(t, getLoadBalance(*appConfig, nil) == cluster.LoadBalance(lb), Equal(true))
+
+	logger := &testStructuredLogger{}
+	appConfig.Logger = logger
+	appConfig.Init()
+	appConfig.LoadBalance = "notExist"
+	Assert(t, getLoadBalance(*appConfig, nil), Equal(extension.GetLoadBalance()))
+	Assert(t, logger.msgs, Equal([]string{"load balance not found, use default load balance"}))
+	appConfig.Logger = nil
+	appConfig.Init()
+
+	appConfig.LoadBalance = "observer"
+	appConfig.Zone = "az-a"
+	zoneLB, ok := getLoadBalance(*appConfig, nil).(*zone.LoadBalance)
+	Assert(t, ok, Equal(true))
+	Assert(t, zoneLB.Zone, Equal("az-a"))
+	Assert(t, zoneLB.Next == cluster.LoadBalance(lb), Equal(true))
+}
+
+func TestRequestRecoveryObserveLoadBalance(t *testing.T) {
+	ts := runStatusCodeResponse(http.StatusOK)
+	defer ts.Close()
+	appConfig := getTestAppConfig()
+	appConfig.IP = "http://observer:8080"
+	appConfig.LoadBalance = "observerRequest"
+	lb := &testObserverLoadBalance{}
+	extension.AddLoadBalance("observerRequest", lb)
+	server.SetServers(appConfig.GetHost(), map[string]*config.ServerInfo{
+		ts.URL: {HomepageURL: ts.URL + "/"},
+	})
+	server.SetNextTryConnTime(appConfig.GetHost(), 10)
+
+	_, err := RequestRecovery(*appConfig, &env.ConnectConfig{URI: "configs"}, nil)
+	Assert(t, err, NilVal())
+	Assert(t, lb.started, Equal([]string{ts.URL + "/"}))
+	Assert(t, len(lb.costs), Equal(1))
+
+	// 长轮询请求不反馈
+	_, err = RequestRecovery(*appConfig, &env.ConnectConfig{URI: "configs", IsLongPoll: true}, nil)
+	Assert(t, err, NilVal())
+	Assert(t, len(lb.started), Equal(1))
+}
+
+func TestRequestRecoveryAllNodesDown(t *testing.T) {
+	server.SetCircuitBreaker(1, 30*time.Millisecond)
+	defer server.SetCircuitBreaker(1, 10*time.Second)
+
+	var requests int32
+	serverMap := make(map[string]*config.ServerInfo)
+	for i := 0; i < 3; i++ {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			// 请求耗时超过熔断时间，节点在本次调用中会转为半开状态
+			time.Sleep(40 * time.Millisecond)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+		serverMap[ts.URL] = &config.ServerInfo{HomepageURL: ts.URL + "/"}
+	}
+	appConfig := getTestAppConfig()
+	servers := server.NewServers()
+	servers.SetServers(appConfig.GetHost(), serverMap)
+	servers.SetNextTryConnTime(appConfig.GetHost(), 60)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := RequestRecoveryContext(ctx, *appConfig, &env.ConnectConfig{
+		URI:        getConfigURLSuffix(appConfig, appConfig.NamespaceName),
+		Extensions: &env.Extensions{Servers: servers},
+	}, nil)
+	Assert(t, err, NotNilVal())
+	Assert(t, ctx.Err(), NilVal())
+	Assert(t, atomic.LoadInt32(&requests), Equal(int32(3)))
+}
+
+func TestRequestRecoveryAllMetaHostsDown(t *testing.T) {
+	server.SetCircuitBreaker(1, 30*time.Millisecond)
+	defer server.SetCircuitBreaker(1, 10*time.Second)
+
+	var requests int32
+	hosts := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			time.Sleep(40 * time.Millisecond)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+		hosts = append(hosts, ts.URL)
+	}
+	appConfig := getTestAppConfig()
+	appConfig.IP = strings.Join(hosts, ",")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := RequestRecoveryContext(ctx, *appConfig, &env.ConnectConfig{
+		URI:        getConfigURLSuffix(appConfig, appConfig.NamespaceName),
+		Extensions: &env.Extensions{Servers: server.NewServers()},
+	}, nil)
+	Assert(t, err, NotNilVal())
+	Assert(t, ctx.Err(), NilVal())
+	Assert(t, atomic.LoadInt32(&requests), Equal(int32(3)))
+}
+
+func TestRequestRecoveryMetaFallbackToServices(t *testing.T) {
+	var metaRequests int32
+	hosts := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&metaRequests, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+		hosts = append(hosts, ts.URL)
+	}
+	node := runStatusCodeResponse(http.StatusOK)
+	defer node.Close()
+	appConfig := getTestAppConfig()
+	appConfig.IP = strings.Join(hosts, ",")
+	servers := server.NewServers()
+	servers.SetServers(appConfig.GetHost(), map[string]*config.ServerInfo{
+		node.URL: {HomepageURL: node.URL + "/"},
+	})
+
+	_, err := RequestRecoveryContext(context.Background(), *appConfig, &env.ConnectConfig{
+		URI:        "configs",
+		Extensions: &env.Extensions{Servers: servers},
+	}, nil)
+	Assert(t, err, NilVal())
+	Assert(t, atomic.LoadInt32(&metaRequests), Equal(int32(2)))
+	Assert(t, servers.AllowHost(hosts[0]+"/"), Equal(false))
+}
+
+func TestRequestRecoveryMetaFailover(t *testing.T) {
+	down := runStatusCodeResponse(http.StatusInternalServerError)
+	defer down.Close()
+	ts := runStatusCodeResponse(http.StatusOK)
+	defer ts.Close()
+	appConfig := getTestAppConfig()
+	appConfig.IP = down.URL + "," + ts.URL
+
+	_, err := RequestRecovery(*appConfig, &env.ConnectConfig{URI: "configs"}, nil)
+	Assert(t, err, NilVal())
+	Assert(t, server.GetNodeState(down.URL+"/"), Equal(server.StateOpen))
+	Assert(t, server.GetNodeState(ts.URL+"/"), Equal(server.StateClosed))
+
+	host, _ := loadBalance(*appConfig, nil, nil)
+	Assert(t, host, Equal(ts.URL+"/"))
+}
+
+type testMetrics struct {
+	metrics.NoopMetrics
+	requests []string
+	retries  int32
+}
+
+func (m *testMetrics) ObserveRequest(api string, statusCode int, cost time.Duration) {
+	m.requests = append(m.requests, fmt.Sprintf("%s:%d", api, statusCode))
+}
+
+func (m *testMetrics) IncRetry(api string) {
+	atomic.AddInt32(&m.retries, 1)
+}
+
+func TestRequestMetrics(t *testing.T) {
+	m := &testMetrics{}
+	extension.SetMetrics(m)
+	defer extension.SetMetrics(nil)
+	extension.SetRetryPolicy(&backoff.ExponentialBackOff{MaxAttempts: 2})
+	defer extension.SetRetryPolicy(nil)
+
+	ts := runStatusCodeResponse(http.StatusInternalServerError)
+	defer ts.Close()
+
+	_, err := Request(ts.URL+"/configs/app/default/application", &env.ConnectConfig{IsRetry: true}, nil)
+	Assert(t, err, NotNilVal())
+	Assert(t, m.requests, Equal([]string{"configs:500", "configs:500"}))
+	Assert(t, atomic.LoadInt32(&m.retries), Equal(int32(1)))
+}
+
+func TestRequestAPI(t *testing.T) {
+	Assert(t, requestAPI("http://localhost:8080/notifications/v2?appId=test"), Equal(apiNotifications))
+	Assert(t, requestAPI("http://localhost:8080/apollo/configs/test/default/application"), Equal(apiConfigs))
+	Assert(t, requestAPI("http://localhost:8080/configfiles/json/test/default/application"), Equal(apiConfigFiles))
+	Assert(t, requestAPI("http://localhost:8080/services/config?appId=test"), Equal(apiServices))
+	Assert(t, requestAPI("http://localhost:8080/"), Equal(apiOther))
+}
+
+func TestRequestRecoveryTracing(t *testing.T) {
+	tracer := &recordTracer{}
+	extension.SetTracer(tracer)
+	defer extension.SetTracer(nil)
+
+	ts := runStatusCodeResponse(http.StatusOK)
+	defer ts.Close()
+	appConfig := getTestAppConfig()
+	appConfig.IP = ts.URL
+
+	_, err := RequestRecovery(*appConfig, &env.ConnectConfig{URI: "configs/test/dev/application", AppID: appConfig.AppID},
+		&CallBack{Namespace: "application"})
+	Assert(t, err, NilVal())
+
+	spans := tracer.getSpans()
+	Assert(t, len(spans), Equal(1))
+	Assert(t, spans[0].name, Equal(tracing.SpanRequest))
+	attrs := make(map[string]interface{})
+	for _, attr := range spans[0].attrs {
+		attrs[attr.Key] = attr.Value
+	}
+	Assert(t, attrs[tracing.AppID], Equal("test"))
+	Assert(t, attrs[tracing.Cluster], Equal("dev"))
+	Assert(t, attrs[tracing.Namespace], Equal("application"))
+	Assert(t, attrs[tracing.API], Equal(apiConfigs))
+	Assert(t, attrs[tracing.HTTPStatusCode], Equal(http.StatusOK))
+}
+
+type recordTracer struct {
+	lock  sync.Mutex
+	spans []*recordSpan
+}
+
+func (r *recordTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	span := &recordSpan{tracer: r, name: name, attrs: attrs}
+	return ctx, span
+}
+
+func (r *recordTracer) getSpans() []*recordSpan {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*recordSpan(nil), r.spans...)
+}
+
+type recordSpan struct {
+	tracer *recordTracer
+	name   string
+	attrs  []tracing.Attribute
+}
+
+func (s *recordSpan) SetAttributes(attrs ...tracing.Attribute) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+func (s *recordSpan) End(err error) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
 func mockIPList(t *testing.T, appConfigFunc func() config.AppConfig) {
 	time.Sleep(1 * time.Second)
 
@@ -0,0 +1,146 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/utils"
+)
+
+// tlsVersions MinVersion 可选的值
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ValidateTLSConfig 校验 TLS 配置，读取 CA 证书与客户端证书，配置错误时在创建客户端时返回错误而不是每次请求失败
+func ValidateTLSConfig(tlsConfig config.TLSConfig) error {
+	_, err := newTLSConfig(tlsConfig)
+	return err
+}
+
+// newTLSConfig 根据配置创建 tls.Config，默认校验服务端证书；
+// CA 证书只在创建时读取一次，客户端证书在文件修改后的下一次握手时重新加载
+func newTLSConfig(tlsConfig config.TLSConfig) (*tls.Config, error) {
+	if tlsConfig == (config.TLSConfig{}) {
+		return nil, nil
+	}
+
+	c := &tls.Config{
+		ServerName:         tlsConfig.ServerName,
+		InsecureSkipVerify: tlsConfig.InsecureSkipVerify,
+	}
+
+	if tlsConfig.MinVersion != utils.Empty {
+		version, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(tlsConfig.MinVersion), "tls")]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls min version: %s", tlsConfig.MinVersion)
+		}
+		c.MinVersion = version
+	}
+
+	if tlsConfig.CAFile != utils.Empty {
+		pem, err := os.ReadFile(tlsConfig.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls ca file fail: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in tls ca file: %s", tlsConfig.CAFile)
+		}
+		c.RootCAs = pool
+	}
+
+	if tlsConfig.CertFile != utils.Empty || tlsConfig.KeyFile != utils.Empty {
+		reloader, err := newCertificateReloader(tlsConfig.CertFile, tlsConfig.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		c.GetClientCertificate = reloader.GetClientCertificate
+	}
+	return c, nil
+}
+
+// certificateReloader 证书或私钥文件修改后重新加载客户端证书，证书轮换后无需重启客户端
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+	lock     sync.Mutex
+	cert     *tls.Certificate
+	// modTime 已加载证书的文件修改时间
+	modTime time.Time
+}
+
+func newCertificateReloader(certFile string, keyFile string) (*certificateReloader, error) {
+	r := &certificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return nil, fmt.Errorf("load tls client certificate fail: %w", err)
+	}
+	if err = r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetClientCertificate 实现 tls.Config.GetClientCertificate，
+// 重新加载失败时（如证书与私钥尚未全部写入）继续使用已加载的证书
+func (r *certificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	modTime, err := r.latestModTime()
+	if err == nil && modTime.After(r.modTime) {
+		_ = r.load(modTime)
+	}
+	return r.cert, nil
+}
+
+func (r *certificateReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load tls client certificate fail: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// latestModTime 获取证书与私钥文件中较晚的修改时间
+func (r *certificateReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/env"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+func writeCertFile(t *testing.T, cert *x509.Certificate) string {
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600)
+	Assert(t, err, NilVal())
+	return file
+}
+
+// writeClientCert 生成自签名的客户端证书，返回证书、证书文件与私钥文件
+func writeClientCert(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Assert(t, err, NilVal())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "agollo-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Assert(t, err, NilVal())
+	cert, err := x509.ParseCertificate(der)
+	Assert(t, err, NilVal())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	Assert(t, err, NilVal())
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	Assert(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600), NilVal())
+	Assert(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600), NilVal())
+	return cert, certFile, keyFile
+}
+
+func runTLSResponse() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestHttpsRequestVerifyServerCertificate(t *testing.T) {
+	server := runTLSResponse()
+	defer server.Close()
+
+	_, err := Request(server.URL, &env.ConnectConfig{}, nil)
+	Assert(t, err, NotNilVal())
+
+	_, err = Request(server.URL, &env.ConnectConfig{
+		TLS: config.TLSConfig{CAFile: writeCertFile(t, server.Certificate())},
+	}, nil)
+	Assert(t, err, NilVal())
+
+	_, err = Request(server.URL, &env.ConnectConfig{
+		TLS: config.TLSConfig{InsecureSkipVerify: true},
+	}, nil)
+	Assert(t, err, NilVal())
+}
+
+func TestHttpsRequestServerName(t *testing.T) {
+	server := runTLSResponse()
+	defer server.Close()
+	caFile := writeCertFile(t, server.Certificate())
+
+	// httptest 的证书包含 example.com
+	_, err := Request(server.URL, &env.ConnectConfig{
+		TLS: config.TLSConfig{CAFile: caFile, ServerName: "example.com"},
+	}, nil)
+	Assert(t, err, NilVal())
+
+	_, err = Request(server.URL, &env.ConnectConfig{
+		TLS: config.TLSConfig{CAFile: caFile, ServerName: "apollo.invalid"},
+	}, nil)
+	Assert(t, err, NotNilVal())
+}
+
+func TestHttpsRequestMutualTLS(t *testing.T) {
+	clientCert, certFile, keyFile := writeClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writeCertFile(t, server.Certificate())
+
+	_, err := Request(server.URL, &env.ConnectConfig{
+		TLS: config.TLSConfig{CAFile: caFile},
+	}, nil)
+	Assert(t, err, NotNilVal())
+
+	_, err = Request(server.URL, &env.ConnectConfig{
+		TLS: config.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+	}, nil)
+	Assert(t, err, NilVal())
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	c, err := newTLSConfig(config.TLSConfig{})
+	Assert(t, err, NilVal())
+	Assert(t, c, NilVal())
+
+	c, err = newTLSConfig(config.TLSConfig{MinVersion: "1.3"})
+	Assert(t, err, NilVal())
+	Assert(t, c.MinVersion, Equal(uint16(tls.VersionTLS13)))
+	Assert(t, c.InsecureSkipVerify, Equal(false))
+
+	c, err = newTLSConfig(config.TLSConfig{MinVersion: "TLS1.2"})
+	Assert(t, err, NilVal())
+	Assert(t, c.MinVersion, Equal(uint16(tls.VersionTLS12)))
+
+	_, err = newTLSConfig(config.TLSConfig{MinVersion: "2.0"})
+	Assert(t, err, NotNilVal())
+
+	_, err = newTLSConfig(config.TLSConfig{CAFile: filepath.Join(t.TempDir(), "not-exist.pem")})
+	Assert(t, err, NotNilVal())
+
+	_, err = newTLSConfig(config.TLSConfig{CertFile: filepath.Join(t.TempDir(), "not-exist.pem")})
+	Assert(t, err, NotNilVal())
+}
+
+func TestCertificateReloader(t *testing.T) {
+	cert, certFile, keyFile := writeClientCert(t)
+	reloader, err := newCertificateReloader(certFile, keyFile)
+	Assert(t, err, NilVal())
+	got, err := reloader.GetClientCertificate(nil)
+	Assert(t, err, NilVal())
+	Assert(t, got.Certificate[0], Equal(cert.Raw))
+
+	rotated, rotatedCertFile, rotatedKeyFile := writeClientCert(t)
+	modTime := time.Now().Add(time.Minute)
+	for src, dst := range map[string]string{rotatedCertFile: certFile, rotatedKeyFile: keyFile} {
+		b, err := os.ReadFile(src)
+		Assert(t, err, NilVal())
+		Assert(t, os.WriteFile(dst, b, 0600), NilVal())
+		Assert(t, os.Chtimes(dst, modTime, modTime), NilVal())
+	}
+	got, err = reloader.GetClientCertificate(nil)
+	Assert(t, err, NilVal())
+	Assert(t, got.Certificate[0], Equal(rotated.Raw))
+
+	// 重新加载失败时继续使用已加载的证书
+	Assert(t, os.WriteFile(keyFile, []byte("invalid"), 0600), NilVal())
+	Assert(t, os.Chtimes(keyFile, modTime.Add(time.Minute), modTime.Add(time.Minute)), NilVal())
+	got, err = reloader.GetClientCertificate(nil)
+	Assert(t, err, NilVal())
+	Assert(t, got.Certificate[0], Equal(rotated.Raw))
+}
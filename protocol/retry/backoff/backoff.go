@@ -0,0 +1,106 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ExponentialBackOff 指数退避重试策略，第 n 次重试前等待 InitialInterval * Multiplier^(n-1)，
+// 并在 [1-RandomizationFactor, 1+RandomizationFactor] 范围内随机抖动，避免大量客户端同时重试
+type ExponentialBackOff struct {
+	// InitialInterval 首次重试前等待的时间
+	InitialInterval time.Duration
+	// MaxInterval 单次等待的最长时间，<=0 不限制
+	MaxInterval time.Duration
+	// Multiplier 每次重试等待时间的增长倍数，<1 时按 1 处理（固定间隔）
+	Multiplier float64
+	// RandomizationFactor 随机抖动系数，取值 [0, 1]，0 表示不抖动
+	RandomizationFactor float64
+	// MaxAttempts 最多请求次数（含首次请求），<=0 不限制
+	MaxAttempts int
+	// MaxElapsedTime 从首次请求开始允许重试的最长时间，<=0 不限制
+	MaxElapsedTime time.Duration
+	// RetryableStatusClasses 可以重试的状态码类别，如 5 表示 5xx
+	RetryableStatusClasses []int
+	// RetryableStatusCodes 可以重试的状态码，如 429；与 RetryableStatusClasses 均为空时所有状态码都可以重试
+	RetryableStatusCodes []int
+}
+
+// NewExponentialBackOff 创建推荐配置的指数退避重试策略：
+// 首次等待 1s，每次翻倍，最长 30s，抖动 50%，最多请求 5 次，最长 1 分钟，仅重试 5xx 与 429
+func NewExponentialBackOff() *ExponentialBackOff {
+	return &ExponentialBackOff{
+		InitialInterval:        time.Second,
+		MaxInterval:            30 * time.Second,
+		Multiplier:             2,
+		RandomizationFactor:    0.5,
+		MaxAttempts:            5,
+		MaxElapsedTime:         time.Minute,
+		RetryableStatusClasses: []int{5},
+		RetryableStatusCodes:   []int{429},
+	}
+}
+
+// Retryable 网络错误总是可以重试，状态码按 RetryableStatusClasses 与 RetryableStatusCodes 判断
+func (e *ExponentialBackOff) Retryable(statusCode int) bool {
+	if statusCode == 0 {
+		return true
+	}
+	if len(e.RetryableStatusClasses) == 0 && len(e.RetryableStatusCodes) == 0 {
+		return true
+	}
+	for _, class := range e.RetryableStatusClasses {
+		if statusCode/100 == class {
+			return true
+		}
+	}
+	for _, code := range e.RetryableStatusCodes {
+		if statusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NextBackOff 计算第 attempt 次请求失败后的等待时间
+func (e *ExponentialBackOff) NextBackOff(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if e.MaxAttempts > 0 && attempt >= e.MaxAttempts {
+		return 0, false
+	}
+
+	multiplier := e.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	interval := float64(e.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if e.MaxInterval > 0 && interval > float64(e.MaxInterval) {
+		interval = float64(e.MaxInterval)
+	}
+	if e.RandomizationFactor > 0 {
+		delta := e.RandomizationFactor * interval
+		interval = interval - delta + rand.Float64()*2*delta
+	}
+	backOff := time.Duration(math.MaxInt64)
+	if interval < math.MaxInt64 {
+		backOff = time.Duration(interval)
+	}
+	if e.MaxElapsedTime > 0 && elapsed+backOff > e.MaxElapsedTime {
+		return 0, false
+	}
+	return backOff, true
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/protocol/retry"
+)
+
+var exponentialBackOff retry.RetryPolicy = &ExponentialBackOff{}
+
+func TestExponentialBackOff_NextBackOff(t *testing.T) {
+	e := &ExponentialBackOff{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+		MaxAttempts:     5,
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, d := range expected {
+		backOff, ok := e.NextBackOff(i+1, 0)
+		Assert(t, ok, Equal(true))
+		Assert(t, backOff, Equal(d))
+	}
+
+	_, ok := e.NextBackOff(5, 0)
+	Assert(t, ok, Equal(false))
+}
+
+func TestExponentialBackOff_Fixed(t *testing.T) {
+	e := &ExponentialBackOff{InitialInterval: 2 * time.Second}
+
+	for attempt := 1; attempt < 100; attempt++ {
+		backOff, ok := e.NextBackOff(attempt, 0)
+		Assert(t, ok, Equal(true))
+		Assert(t, backOff, Equal(2*time.Second))
+	}
+}
+
+func TestExponentialBackOff_Jitter(t *testing.T) {
+	e := &ExponentialBackOff{
+		InitialInterval:     time.Second,
+		RandomizationFactor: 0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		backOff, ok := e.NextBackOff(1, 0)
+		Assert(t, ok, Equal(true))
+		Assert(t, backOff >= 500*time.Millisecond, Equal(true))
+		Assert(t, backOff <= 1500*time.Millisecond, Equal(true))
+	}
+}
+
+func TestExponentialBackOff_MaxElapsedTime(t *testing.T) {
+	e := &ExponentialBackOff{
+		InitialInterval: time.Second,
+		MaxElapsedTime:  10 * time.Second,
+	}
+
+	_, ok := e.NextBackOff(1, 8*time.Second)
+	Assert(t, ok, Equal(true))
+
+	_, ok = e.NextBackOff(1, 9500*time.Millisecond)
+	Assert(t, ok, Equal(false))
+}
+
+func TestExponentialBackOff_Overflow(t *testing.T) {
+	e := &ExponentialBackOff{
+		InitialInterval: time.Second,
+		Multiplier:      10,
+	}
+
+	backOff, ok := e.NextBackOff(100, 0)
+	Assert(t, ok, Equal(true))
+	Assert(t, backOff > 0, Equal(true))
+}
+
+func TestExponentialBackOff_Retryable(t *testing.T) {
+	Assert(t, exponentialBackOff.Retryable(0), Equal(true))
+	Assert(t, exponentialBackOff.Retryable(500), Equal(true))
+	Assert(t, exponentialBackOff.Retryable(403), Equal(true))
+
+	e := NewExponentialBackOff()
+	Assert(t, e.Retryable(0), Equal(true))
+	Assert(t, e.Retryable(502), Equal(true))
+	Assert(t, e.Retryable(429), Equal(true))
+	Assert(t, e.Retryable(403), Equal(false))
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import "time"
+
+// RetryPolicy 请求 apollo 失败后的重试策略，会被多个 goroutine 同时调用，实现需要保证并发安全
+type RetryPolicy interface {
+	// Retryable 判断请求失败后是否可以重试，statusCode 为 0 表示网络错误
+	Retryable(statusCode int) bool
+	// NextBackOff 第 attempt 次（从 1 开始）请求失败后，返回下次重试前需要等待的时间，
+	// elapsed 为首次请求至今经过的时间，返回 false 表示不再重试
+	NextBackOff(attempt int, elapsed time.Duration) (time.Duration, bool)
+}
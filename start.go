@@ -51,6 +51,18 @@ func SetLogger(loggerInterface log.LoggerInterface) {
 	}
 }
 
+// SetStructuredLogger 设置自定义结构化日志组件，单个客户端可以通过 AppConfig.Logger 单独设置
+func SetStructuredLogger(logger log.StructuredLogger) {
+	if logger != nil {
+		log.SetStructuredLogger(logger)
+	}
+}
+
+// SetLogLevel 设置全局日志级别，单个客户端可以通过 AppConfig.LogLevel 单独设置
+func SetLogLevel(level log.Level) {
+	log.SetLevel(level)
+}
+
 // SetCache 设置自定义cache组件
 func SetCache(cacheFactory agcache.CacheFactory) {
 	if cacheFactory != nil {
@@ -15,8 +15,10 @@
 package agollo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -24,6 +26,7 @@ import (
 	. "github.com/tevid/gohamcrest"
 
 	"github.com/apolloconfig/agollo/v5/agcache/memory"
+	"github.com/apolloconfig/agollo/v5/component"
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
@@ -174,6 +177,26 @@ func TestSetLogger(t *testing.T) {
 	// Assert(t, log.Logger, Equal(logger))
 }
 
+type testStructuredLogger struct {
+}
+
+func (l *testStructuredLogger) Enabled(level log.Level) bool {
+	return true
+}
+
+func (l *testStructuredLogger) Log(level log.Level, msg string, fields ...log.Field) {
+}
+
+func TestSetStructuredLogger(t *testing.T) {
+	defer log.SetStructuredLogger(nil)
+	defer SetLogLevel(log.LevelDebug)
+
+	SetStructuredLogger(&testStructuredLogger{})
+	SetLogLevel(log.LevelWarn)
+	Assert(t, log.Enabled(log.LevelInfo), Equal(false))
+	Assert(t, log.Enabled(log.LevelWarn), Equal(true))
+}
+
 func TestSetCache(t *testing.T) {
 	defaultCacheFactory := &memory.DefaultCacheFactory{}
 	SetCache(defaultCacheFactory)
@@ -270,3 +293,153 @@ func TestStartWithConfigMustReadFromRemote(t *testing.T) {
 	handler := extension.GetFileHandler()
 	Assert(t, handler, NotNilVal())
 }
+
+func TestStartWithContextTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	newAppConfig := getTestAppConfig()
+	newAppConfig.IP = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	client, err := StartWithContext(ctx, func() (*config.AppConfig, error) {
+		return newAppConfig, nil
+	})
+
+	Assert(t, client, Equal(nil))
+	Assert(t, err, Equal(context.DeadlineExceeded))
+	Assert(t, time.Since(start) < time.Second, Equal(true))
+}
+
+func TestSubscribeAndUnsubscribe(t *testing.T) {
+	c := getTestAppConfig()
+	handlerMap := make(map[string]func(http.ResponseWriter, *http.Request), 2)
+	handlerMap["application"] = onlyNormalConfigResponse
+	handlerMap["abc1"] = onlyNormalSecondConfigResponse
+	server := runMockConfigFilesServer(handlerMap, nil, c)
+	defer server.Close()
+	c.IP = server.URL
+
+	client, err := StartWithContext(context.Background(), func() (*config.AppConfig, error) {
+		return c, nil
+	})
+	Assert(t, err, NilVal())
+	defer client.Close()
+	internal := client.(*internalClient)
+
+	Assert(t, client.Subscribe(""), NotNilVal())
+	Assert(t, client.Subscribe("abc1"), NilVal())
+	Assert(t, internal.getAppConfig().NamespaceName, Equal("application,abc1"))
+	Assert(t, client.GetConfig("abc1").GetValue("key1-1"), Equal("value1-1"))
+	Assert(t, client.Subscribe("abc1"), NilVal())
+	Assert(t, internal.getAppConfig().NamespaceName, Equal("application,abc1"))
+
+	Assert(t, client.Unsubscribe("abc1"), NilVal())
+	Assert(t, internal.getAppConfig().NamespaceName, Equal("application"))
+	Assert(t, internal.cache.GetConfig("abc1"), NilVal())
+	Assert(t, client.GetValue("key1"), Equal("value1"))
+	// 取消订阅后获取配置不会重新订阅
+	Assert(t, client.GetConfig("abc1"), NilVal())
+	Assert(t, client.GetConfigCache("abc1"), NilVal())
+	Assert(t, internal.getAppConfig().NamespaceName, Equal("application"))
+
+	Assert(t, client.Unsubscribe("abc1"), NotNilVal())
+	Assert(t, client.Unsubscribe("application"), NotNilVal())
+
+	Assert(t, client.Subscribe("abc1"), NilVal())
+	Assert(t, client.GetConfig("abc1").GetValue("key1-1"), Equal("value1-1"))
+}
+
+func TestSubscribeBeforeStart(t *testing.T) {
+	client, err := New(WithAppConfig(getTestAppConfig()))
+	Assert(t, err, NilVal())
+	Assert(t, client.Subscribe("abc1"), NilVal())
+	Assert(t, client.(*internalClient).getAppConfig().NamespaceName, Equal("application,abc1"))
+	// GetValue 等方法读取默认 namespace，不能取消订阅
+	Assert(t, client.Unsubscribe("application"), NotNilVal())
+	Assert(t, client.(*internalClient).getAppConfig().NamespaceName, Equal("application,abc1"))
+	Assert(t, client.Unsubscribe("abc1"), NilVal())
+	Assert(t, client.(*internalClient).getAppConfig().NamespaceName, Equal("application"))
+}
+
+func TestShutdown(t *testing.T) {
+	c := appConfig
+	handlerMap := make(map[string]func(http.ResponseWriter, *http.Request), 1)
+	handlerMap["application"] = onlyNormalConfigResponse
+	server := runMockConfigFilesServer(handlerMap, nil, c)
+	c.IP = server.URL
+
+	client, err := StartWithContext(context.Background(), func() (*config.AppConfig, error) {
+		return c, nil
+	})
+	Assert(t, err, NilVal())
+	Assert(t, client.GetValue("key1"), Equal("value1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	Assert(t, client.Shutdown(ctx), NilVal())
+
+	for _, comp := range client.(*internalClient).getComponents() {
+		select {
+		case <-comp.(component.Waitable).Done():
+		default:
+			t.Fatalf("component %T still running after Shutdown", comp)
+		}
+	}
+}
+
+type headerAuth struct {
+	value string
+}
+
+func (a *headerAuth) HTTPHeaders(url string, appID string, secret string) map[string][]string {
+	return map[string][]string{"X-Client": {a.value}}
+}
+
+func TestStartWithExtensions(t *testing.T) {
+	c1 := &config.AppConfig{AppID: "app1", Cluster: "dev", NamespaceName: "application"}
+	c2 := &config.AppConfig{AppID: "app2", Cluster: "dev", NamespaceName: "application"}
+	headers := make(chan string, 10)
+	server1 := runMockConfigFilesServer(map[string]func(http.ResponseWriter, *http.Request){
+		"application": func(rw http.ResponseWriter, req *http.Request) {
+			headers <- req.Header.Get("X-Client")
+			onlyNormalConfigResponse(rw, req)
+		},
+	}, nil, c1)
+	defer server1.Close()
+	server2 := runMockConfigFilesServer(map[string]func(http.ResponseWriter, *http.Request){
+		"application": onlyNormalSecondConfigResponse,
+	}, nil, c2)
+	defer server2.Close()
+	c1.IP = server1.URL
+	c2.IP = server2.URL
+
+	fileHandler := &testFileHandler{}
+	client1, err := StartWithExtensions(context.Background(), func() (*config.AppConfig, error) {
+		return c1, nil
+	}, &env.Extensions{HTTPAuth: &headerAuth{value: "one"}, FileHandler: fileHandler})
+	Assert(t, err, NilVal())
+	defer client1.Close()
+	client2, err := StartWithExtensions(context.Background(), func() (*config.AppConfig, error) {
+		return c2, nil
+	}, nil)
+	Assert(t, err, NilVal())
+	defer client2.Close()
+
+	Assert(t, client1.GetValue("key1"), Equal("value1"))
+	Assert(t, client2.GetValue("key1-1"), Equal("value1-1"))
+	Assert(t, client1.GetValue("key1-1"), Equal(""))
+	Assert(t, <-headers, Equal("one"))
+
+	ext1 := client1.(*internalClient).extensions
+	ext2 := client2.(*internalClient).extensions
+	Assert(t, ext1.Servers, NotNilVal())
+	Assert(t, ext1.Servers != ext2.Servers, Equal(true))
+	Assert(t, ext1.GetFileHandler(), Equal(fileHandler))
+}
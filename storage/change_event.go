@@ -29,6 +29,13 @@ type ChangeListener interface {
 	OnNewestChange(event *FullChangeEvent)
 }
 
+// NamespaceChangeListener 只监听单个 namespace 的监听器，取消订阅该 namespace 时会被自动移除
+type NamespaceChangeListener interface {
+	ChangeListener
+	// Namespace 监听的 namespace
+	Namespace() string
+}
+
 // config change type
 type ConfigChangeType int
 
@@ -54,6 +61,8 @@ type ConfigChange struct {
 type FullChangeEvent struct {
 	baseChangeEvent
 	Changes map[string]interface{}
+	// version 对应 Config 的更新次数，用于丢弃乱序到达的旧事件
+	version uint64
 }
 
 // create modify config change
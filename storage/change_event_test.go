@@ -16,6 +16,7 @@ package storage
 
 import (
 	"container/list"
+	"context"
 	"sync"
 	"testing"
 
@@ -79,7 +80,7 @@ func TestPushChangeEvent(t *testing.T) {
 
 	c.AddChangeListener(listener)
 
-	c.pushChangeEvent(event)
+	c.pushChangeEvent(context.Background(), event)
 
 	listener.w.Wait()
 
@@ -0,0 +1,200 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrKeyNotFound 配置项不存在
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrNotInitialized namespace 的配置尚未初始化完成
+	ErrNotInitialized = errors.New("config not initialized")
+	// ErrConvertFailed 配置值无法转换为目标类型
+	ErrConvertFailed = errors.New("type conversion failed")
+)
+
+// Lookup 获取配置值，与 GetXxxValue 不同，获取失败时返回错误而不是默认值：
+// 初始化未完成返回 ErrNotInitialized，配置项不存在返回 ErrKeyNotFound，
+// 类型转换失败的错误包装了 ErrConvertFailed，可以使用 errors.Is 判断；
+// GetXxxValue 会阻塞等待初始化完成，Lookup 及 GetString 等方法不等待，需要等待时先调用 GetWaitInit().Wait()
+func (c *Config) Lookup(key string) (interface{}, error) {
+	if c == nil || !c.GetIsInit() || c.cache == nil {
+		return nil, fmt.Errorf("%w, key:%s", ErrNotInitialized, key)
+	}
+
+	value, err := c.cache.Get(key)
+	if err != nil || value == nil {
+		return nil, fmt.Errorf("%w, namespace:%s key:%s", ErrKeyNotFound, c.namespace, key)
+	}
+	return value, nil
+}
+
+// GetString 获取配置值（string），规则与 Lookup 相同
+func (c *Config) GetString(key string) (string, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return "", err
+	}
+
+	v, ok := value.(string)
+	if !ok {
+		return "", conversionError(key, value, "string", errors.New("not a string"))
+	}
+	return v, nil
+}
+
+// GetInt 获取配置值（int），规则与 Lookup 相同
+func (c *Config) GetInt(key string) (int, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := toInt64(value)
+	if err != nil {
+		return 0, conversionError(key, value, "int", err)
+	}
+	if int64(int(v)) != v {
+		return 0, conversionError(key, value, "int", errors.New("value out of range"))
+	}
+	return int(v), nil
+}
+
+// GetFloat 获取配置值（float64），规则与 Lookup 相同
+func (c *Config) GetFloat(key string) (float64, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := toFloat64(value)
+	if err != nil {
+		return 0, conversionError(key, value, "float64", err)
+	}
+	return v, nil
+}
+
+// GetBool 获取配置值（bool），规则与 Lookup 相同
+func (c *Config) GetBool(key string) (bool, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return false, err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, conversionError(key, value, "bool", err)
+		}
+		return b, nil
+	}
+	return false, conversionError(key, value, "bool", errors.New("unsupported type"))
+}
+
+// GetDuration 获取配置值（time.Duration），解析规则与 GetDurationValue 相同
+func (c *Config) GetDuration(key string) (time.Duration, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := convertDuration(value)
+	if err != nil {
+		return 0, conversionError(key, value, "time.Duration", err)
+	}
+	return v, nil
+}
+
+// GetTime 获取配置值（time.Time），按 layout 解析
+func (c *Config) GetTime(key string, layout string) (time.Time, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	v, err := convertTime(value, layout)
+	if err != nil {
+		return time.Time{}, conversionError(key, value, "time.Time", err)
+	}
+	return v, nil
+}
+
+// GetBytes 获取配置值（字节数），解析规则与 GetBytesValue 相同
+func (c *Config) GetBytes(key string) (int64, error) {
+	value, err := c.Lookup(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := convertBytes(value)
+	if err != nil {
+		return 0, conversionError(key, value, "bytes", err)
+	}
+	return v, nil
+}
+
+func conversionError(key string, value interface{}, target string, err error) error {
+	return fmt.Errorf("%w, key:%s, convert %T to %s fail, error:%v", ErrConvertFailed, key, value, target, err)
+}
+
+func convertDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case int:
+		return time.Duration(v), nil
+	case int64:
+		return time.Duration(v), nil
+	case string:
+		return time.ParseDuration(strings.TrimSpace(v))
+	}
+	return 0, fmt.Errorf("unsupported source type:%T", value)
+}
+
+func convertTime(value interface{}, layout string) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(layout, strings.TrimSpace(v))
+	}
+	return time.Time{}, fmt.Errorf("unsupported source type:%T", value)
+}
+
+func convertBytes(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("float64 value has fractional part: %v", v)
+		}
+		return int64(v), nil
+	case string:
+		return parseBytes(v)
+	}
+	return 0, fmt.Errorf("unsupported source type:%T", value)
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/agcache/memory"
+	. "github.com/tevid/gohamcrest"
+)
+
+func TestLookup(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["string"] = "value"
+	configurations["int"] = 1
+	configurations["intString"] = "2"
+	configurations["float"] = 190.3
+	configurations["bool"] = "true"
+	configurations["timeout"] = "1m"
+	configurations["startAt"] = "2021-07-01"
+	configurations["maxSize"] = "1KB"
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	value, err := config.Lookup("string")
+	Assert(t, err, NilVal())
+	Assert(t, value, Equal("value"))
+
+	s, err := config.GetString("string")
+	Assert(t, err, NilVal())
+	Assert(t, s, Equal("value"))
+
+	i, err := config.GetInt("int")
+	Assert(t, err, NilVal())
+	Assert(t, i, Equal(1))
+
+	i, err = config.GetInt("intString")
+	Assert(t, err, NilVal())
+	Assert(t, i, Equal(2))
+
+	f, err := config.GetFloat("float")
+	Assert(t, err, NilVal())
+	Assert(t, f, Equal(190.3))
+
+	b, err := config.GetBool("bool")
+	Assert(t, err, NilVal())
+	Assert(t, b, Equal(true))
+
+	d, err := config.GetDuration("timeout")
+	Assert(t, err, NilVal())
+	Assert(t, d, Equal(time.Minute))
+
+	tm, err := config.GetTime("startAt", "2006-01-02")
+	Assert(t, err, NilVal())
+	Assert(t, tm, Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	size, err := config.GetBytes("maxSize")
+	Assert(t, err, NilVal())
+	Assert(t, size, Equal(int64(1024)))
+}
+
+func TestLookupErrors(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["string"] = "value"
+	configurations["int"] = 1
+	configurations["float"] = 190.3
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	_, err := config.Lookup("joe")
+	Assert(t, errors.Is(err, ErrKeyNotFound), Equal(true))
+
+	_, err = config.GetInt("joe")
+	Assert(t, errors.Is(err, ErrKeyNotFound), Equal(true))
+
+	_, err = config.GetInt("float")
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	_, err = config.GetInt("string")
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	_, err = config.GetString("int")
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	_, err = config.GetBool("string")
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	_, err = config.GetDuration("string")
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	_, err = config.GetTime("string", time.RFC3339)
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	_, err = config.GetBytes("float")
+	Assert(t, errors.Is(err, ErrConvertFailed), Equal(true))
+
+	notInit := initConfig("notInit", &memory.DefaultCacheFactory{})
+	_, err = notInit.GetString("string")
+	Assert(t, errors.Is(err, ErrNotInitialized), Equal(true))
+
+	var nilConfig *Config
+	_, err = nilConfig.Lookup("string")
+	Assert(t, errors.Is(err, ErrNotInitialized), Equal(true))
+}
@@ -16,17 +16,22 @@ package storage
 
 import (
 	"container/list"
+	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/apolloconfig/agollo/v5/agcache"
 	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env/config"
+	"github.com/apolloconfig/agollo/v5/env/file"
 	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/tracing"
 	"github.com/apolloconfig/agollo/v5/utils"
 )
 
@@ -44,6 +49,47 @@ type Cache struct {
 	apolloConfigCache sync.Map
 	changeListeners   *list.List
 	rw                sync.RWMutex
+	// fileHandler 备份配置使用的文件处理器，为空时使用全局文件处理器
+	fileHandler file.FileHandler
+	// cacheFactory 创建 namespace 缓存使用的工厂，为空时使用全局工厂
+	cacheFactory agcache.CacheFactory
+	// logger 输出日志使用的结构化日志，为空时使用全局日志
+	logger log.StructuredLogger
+}
+
+// SetFileHandler 设置备份配置使用的文件处理器，需在开始同步配置前设置
+func (c *Cache) SetFileHandler(fileHandler file.FileHandler) {
+	c.fileHandler = fileHandler
+}
+
+// SetLogger 设置输出日志使用的结构化日志，同时对已创建的 namespace 生效，需在开始同步配置前设置
+func (c *Cache) SetLogger(logger log.StructuredLogger) {
+	c.logger = logger
+	c.apolloConfigCache.Range(func(key, value interface{}) bool {
+		value.(*Config).logger = logger
+		return true
+	})
+}
+
+func (c *Cache) getLogger() log.StructuredLogger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return log.Global()
+}
+
+func (c *Cache) getFileHandler() file.FileHandler {
+	if c.fileHandler != nil {
+		return c.fileHandler
+	}
+	return extension.GetFileHandler()
+}
+
+func (c *Cache) getCacheFactory() agcache.CacheFactory {
+	if c.cacheFactory != nil {
+		return c.cacheFactory
+	}
+	return extension.GetCacheFactory()
 }
 
 // GetConfig 根据namespace获取apollo配置
@@ -63,19 +109,25 @@ func (c *Cache) GetConfig(namespace string) *Config {
 
 // CreateNamespaceConfig 根据namespace初始化agollo内容配置
 func CreateNamespaceConfig(namespace string) *Cache {
+	return CreateNamespaceConfigWithFactory(namespace, nil)
+}
+
+// CreateNamespaceConfigWithFactory 根据namespace初始化agollo内容配置，使用 cacheFactory 创建缓存，为 nil 时使用全局工厂
+func CreateNamespaceConfigWithFactory(namespace string, cacheFactory agcache.CacheFactory) *Cache {
+	c := &Cache{
+		changeListeners: list.New(),
+		cacheFactory:    cacheFactory,
+	}
 	// config from apollo
-	var apolloConfigCache sync.Map
 	config.SplitNamespaces(namespace, func(namespace string) {
-		if _, ok := apolloConfigCache.Load(namespace); ok {
+		if _, ok := c.apolloConfigCache.Load(namespace); ok {
 			return
 		}
-		c := initConfig(namespace, extension.GetCacheFactory())
-		apolloConfigCache.Store(namespace, c)
+		namespaceConfig := initConfig(namespace, c.getCacheFactory())
+		namespaceConfig.logger = c.logger
+		c.apolloConfigCache.Store(namespace, namespaceConfig)
 	})
-	return &Cache{
-		apolloConfigCache: apolloConfigCache,
-		changeListeners:   list.New(),
-	}
+	return c
 }
 
 func initConfig(namespace string, factory agcache.CacheFactory) *Config {
@@ -94,6 +146,17 @@ type Config struct {
 	cache     agcache.CacheInterface
 	isInit    atomic.Value
 	waitInit  sync.WaitGroup
+	// version 每次 UpdateApolloConfig 完成后递增
+	version atomic.Uint64
+	// logger 输出日志使用的结构化日志，为空时使用全局日志
+	logger log.StructuredLogger
+}
+
+func (c *Config) getLogger() log.StructuredLogger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return log.Global()
 }
 
 // GetIsInit 获取标志
@@ -116,19 +179,19 @@ func (c *Config) getConfigValue(key string, waitInit bool) interface{} {
 	b := c.GetIsInit()
 	if !b {
 		if !waitInit {
-			log.Errorf("getConfigValue fail, init not done, namespace:%s key:%s", c.namespace, key)
+			c.getLogger().Log(log.LevelError, "get config value fail, init not done", log.KV("namespace", c.namespace), log.KV("key", key))
 			return nil
 		}
 		c.waitInit.Wait()
 	}
 	if c.cache == nil {
-		log.Errorf("get config value fail! namespace:%s not exist!", c.namespace)
+		c.getLogger().Log(log.LevelError, "get config value fail, namespace not exist", log.KV("namespace", c.namespace))
 		return nil
 	}
 
 	value, err := c.cache.Get(key)
 	if err != nil {
-		log.Errorf("get config value fail! key:%s, error:%v", key, err)
+		c.getLogger().Log(log.LevelError, "get config value fail", log.KV("namespace", c.namespace), log.KV("key", key), log.KV("error", err))
 		return nil
 	}
 
@@ -144,7 +207,7 @@ func (c *Config) GetValueImmediately(key string) string {
 
 	v, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to string fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to string fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return utils.Empty
 	}
 	return v
@@ -185,7 +248,7 @@ func (c *Config) GetStringSliceValueImmediately(key string, defaultValue []strin
 		return result
 	}
 
-	log.Debugf("convert to []string fail ! source type:%T", value)
+	c.getLogger().Log(log.LevelDebug, "convert to []string fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 	return defaultValue
 }
 
@@ -212,7 +275,7 @@ func (c *Config) GetIntSliceValueImmediately(key string, defaultValue []int) []i
 				// JSON/YAML 数字默认解析为 float64
 				// 验证值是否为整数（无小数部分）
 				if v != float64(int(v)) {
-					log.Debugf("convert to []int fail! float64 value has fractional part: %v", v)
+					c.getLogger().Log(log.LevelDebug, "convert to []int fail, float64 value has fractional part", log.KV("key", key), log.KV("value", v))
 					return defaultValue
 				}
 				result = append(result, int(v))
@@ -220,18 +283,18 @@ func (c *Config) GetIntSliceValueImmediately(key string, defaultValue []int) []i
 				if i, err := strconv.Atoi(v); err == nil {
 					result = append(result, i)
 				} else {
-					log.Debugf("convert to []int fail! value:%s, source type:%T", v, v)
+					c.getLogger().Log(log.LevelDebug, "convert to []int fail", log.KV("key", key), log.KV("value", v))
 					return defaultValue
 				}
 			default:
-				log.Debugf("convert to []int fail! value:%v, source type:%T", item, item)
+				c.getLogger().Log(log.LevelDebug, "convert to []int fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", item)))
 				return defaultValue
 			}
 		}
 		return result
 	}
 
-	log.Debugf("convert to []int fail ! source type:%T", value)
+	c.getLogger().Log(log.LevelDebug, "convert to []int fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 	return defaultValue
 }
 
@@ -244,7 +307,7 @@ func (c *Config) GetSliceValueImmediately(key string, defaultValue []interface{}
 
 	v, ok := value.([]interface{})
 	if !ok {
-		log.Debugf("convert to []interface{} fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to []interface{} fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 	return v
@@ -264,13 +327,13 @@ func (c *Config) GetIntValueImmediately(key string, defaultValue int) int {
 
 	s, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to int fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to int fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 
 	v, err := strconv.Atoi(s)
 	if err != nil {
-		log.Debugf("Atoi fail, error:%v", err)
+		c.getLogger().Log(log.LevelDebug, "Atoi fail", log.KV("key", key), log.KV("error", err))
 		return defaultValue
 	}
 
@@ -291,13 +354,13 @@ func (c *Config) GetFloatValueImmediately(key string, defaultValue float64) floa
 
 	s, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to float64 fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to float64 fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 
 	v, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		log.Debugf("ParseFloat fail, error:%v", err)
+		c.getLogger().Log(log.LevelDebug, "ParseFloat fail", log.KV("key", key), log.KV("error", err))
 		return defaultValue
 	}
 
@@ -318,19 +381,48 @@ func (c *Config) GetBoolValueImmediately(key string, defaultValue bool) bool {
 
 	s, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to bool fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to bool fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 
 	v, err := strconv.ParseBool(s)
 	if err != nil {
-		log.Debugf("ParseBool fail, error:%v", err)
+		c.getLogger().Log(log.LevelDebug, "ParseBool fail", log.KV("key", key), log.KV("error", err))
 		return defaultValue
 	}
 
 	return v
 }
 
+// GetDurationValueImmediately 获取配置值（time.Duration），获取不到则取默认值，立即返回，初始化未完成直接返回错误
+func (c *Config) GetDurationValueImmediately(key string, defaultValue time.Duration) time.Duration {
+	return c.toDurationValue(c.getConfigValue(key, false), defaultValue)
+}
+
+// GetTimeValueImmediately 获取配置值（time.Time），按 layout 解析，获取不到则取默认值，立即返回，初始化未完成直接返回错误
+func (c *Config) GetTimeValueImmediately(key string, layout string, defaultValue time.Time) time.Time {
+	return c.toTimeValue(c.getConfigValue(key, false), layout, defaultValue)
+}
+
+// GetBytesValueImmediately 获取配置值（字节数，如 64MB），获取不到则取默认值，立即返回，初始化未完成直接返回错误
+func (c *Config) GetBytesValueImmediately(key string, defaultValue int64) int64 {
+	return c.toBytesValue(c.getConfigValue(key, false), defaultValue)
+}
+
+// GetStringMapValueImmediately 获取 prefix. 下的所有配置项，获取不到则取默认值，立即返回，初始化未完成直接返回错误
+func (c *Config) GetStringMapValueImmediately(prefix string, defaultValue map[string]interface{}) map[string]interface{} {
+	m := subValues(c.getConfigValues(prefix, false), prefix)
+	if len(m) == 0 {
+		return defaultValue
+	}
+	return m
+}
+
+// GetSubConfigImmediately 获取 prefix. 下的所有配置项并组装为嵌套 map，立即返回，初始化未完成直接返回错误
+func (c *Config) GetSubConfigImmediately(prefix string) map[string]interface{} {
+	return c.nestValues(subValues(c.getConfigValues(prefix, false), prefix))
+}
+
 // GetValue 获取配置值（string）
 func (c *Config) GetValue(key string) string {
 	value := c.getConfigValue(key, true)
@@ -340,7 +432,7 @@ func (c *Config) GetValue(key string) string {
 
 	v, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to string fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to string fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return utils.Empty
 	}
 	return v
@@ -386,7 +478,7 @@ func (c *Config) GetStringSliceValue(key, separator string, defaultValue []strin
 		return strings.Split(s, separator)
 	}
 
-	log.Debugf("convert to []string fail ! source type:%T", value)
+	c.getLogger().Log(log.LevelDebug, "convert to []string fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 	return defaultValue
 }
 
@@ -413,7 +505,7 @@ func (c *Config) GetIntSliceValue(key, separator string, defaultValue []int) []i
 				// JSON/YAML 数字默认解析为 float64
 				// 验证值是否为整数（无小数部分）
 				if v != float64(int(v)) {
-					log.Debugf("convert to []int fail! float64 value has fractional part: %v", v)
+					c.getLogger().Log(log.LevelDebug, "convert to []int fail, float64 value has fractional part", log.KV("key", key), log.KV("value", v))
 					return defaultValue
 				}
 				result = append(result, int(v))
@@ -421,11 +513,11 @@ func (c *Config) GetIntSliceValue(key, separator string, defaultValue []int) []i
 				if i, err := strconv.Atoi(v); err == nil {
 					result = append(result, i)
 				} else {
-					log.Debugf("convert to []int fail! value:%s, source type:%T", v, v)
+					c.getLogger().Log(log.LevelDebug, "convert to []int fail", log.KV("key", key), log.KV("value", v))
 					return defaultValue
 				}
 			default:
-				log.Debugf("convert to []int fail! value:%v, source type:%T", item, item)
+				c.getLogger().Log(log.LevelDebug, "convert to []int fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", item)))
 				return defaultValue
 			}
 		}
@@ -441,7 +533,7 @@ func (c *Config) GetIntSliceValue(key, separator string, defaultValue []int) []i
 	for index := range sl {
 		i, err := strconv.Atoi(sl[index])
 		if err != nil {
-			log.Debugf("convert to []int fail! value:%s,  source type:%T", sl[index], sl[index])
+			c.getLogger().Log(log.LevelDebug, "convert to []int fail", log.KV("key", key), log.KV("value", sl[index]))
 			return defaultValue
 		}
 		result = append(result, i)
@@ -458,7 +550,7 @@ func (c *Config) GetSliceValue(key string, defaultValue []interface{}) []interfa
 
 	v, ok := value.([]interface{})
 	if !ok {
-		log.Debugf("convert to []interface{} fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to []interface{} fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 	return v
@@ -478,13 +570,13 @@ func (c *Config) GetIntValue(key string, defaultValue int) int {
 
 	s, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to int fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to int fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 
 	v, err := strconv.Atoi(s)
 	if err != nil {
-		log.Debugf("Atoi fail, error:%v", err)
+		c.getLogger().Log(log.LevelDebug, "Atoi fail", log.KV("key", key), log.KV("error", err))
 		return defaultValue
 	}
 	return v
@@ -504,13 +596,13 @@ func (c *Config) GetFloatValue(key string, defaultValue float64) float64 {
 
 	s, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to float64 fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to float64 fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 
 	v, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		log.Debugf("ParseFloat fail, error:%v", err)
+		c.getLogger().Log(log.LevelDebug, "ParseFloat fail", log.KV("key", key), log.KV("error", err))
 		return defaultValue
 	}
 	return v
@@ -530,50 +622,278 @@ func (c *Config) GetBoolValue(key string, defaultValue bool) bool {
 
 	s, ok := value.(string)
 	if !ok {
-		log.Debugf("convert to bool fail ! source type:%T", value)
+		c.getLogger().Log(log.LevelDebug, "convert to bool fail", log.KV("key", key), log.KV("type", fmt.Sprintf("%T", value)))
 		return defaultValue
 	}
 
 	v, err := strconv.ParseBool(s)
 	if err != nil {
-		log.Debugf("ParseBool fail, error:%v", err)
+		c.getLogger().Log(log.LevelDebug, "ParseBool fail", log.KV("key", key), log.KV("error", err))
+		return defaultValue
+	}
+	return v
+}
+
+// GetDurationValue 获取配置值（time.Duration），获取不到则取默认值
+// 字符串使用 time.ParseDuration 解析（如 1m30s），整数按纳秒处理
+func (c *Config) GetDurationValue(key string, defaultValue time.Duration) time.Duration {
+	return c.toDurationValue(c.getConfigValue(key, true), defaultValue)
+}
+
+// GetTimeValue 获取配置值（time.Time），按 layout 解析，获取不到则取默认值
+func (c *Config) GetTimeValue(key string, layout string, defaultValue time.Time) time.Time {
+	return c.toTimeValue(c.getConfigValue(key, true), layout, defaultValue)
+}
+
+// GetBytesValue 获取配置值（字节数），获取不到则取默认值
+// 支持 B、K/KB/KiB、M/MB/MiB、G/GB/GiB、T/TB/TiB、P/PB/PiB 单位（不区分大小写，均按 1024 进制），无单位按字节处理
+func (c *Config) GetBytesValue(key string, defaultValue int64) int64 {
+	return c.toBytesValue(c.getConfigValue(key, true), defaultValue)
+}
+
+func (c *Config) toDurationValue(value interface{}, defaultValue time.Duration) time.Duration {
+	if value == nil {
+		return defaultValue
+	}
+
+	v, err := convertDuration(value)
+	if err != nil {
+		c.getLogger().Log(log.LevelDebug, "convert to time.Duration fail", log.KV("error", err))
+		return defaultValue
+	}
+	return v
+}
+
+func (c *Config) toTimeValue(value interface{}, layout string, defaultValue time.Time) time.Time {
+	if value == nil {
+		return defaultValue
+	}
+
+	v, err := convertTime(value, layout)
+	if err != nil {
+		c.getLogger().Log(log.LevelDebug, "convert to time.Time fail", log.KV("error", err))
+		return defaultValue
+	}
+	return v
+}
+
+func (c *Config) toBytesValue(value interface{}, defaultValue int64) int64 {
+	if value == nil {
+		return defaultValue
+	}
+
+	v, err := convertBytes(value)
+	if err != nil {
+		c.getLogger().Log(log.LevelDebug, "convert to bytes fail", log.KV("error", err))
 		return defaultValue
 	}
 	return v
 }
 
+// bytesUnits 字节单位，均按 1024 进制
+var bytesUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1 << 40,
+	"tib": 1 << 40,
+	"p":   1 << 50,
+	"pb":  1 << 50,
+	"pib": 1 << 50,
+}
+
+// parseBytes 解析 64MB、1.5GiB、1024 等字节数
+func parseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && c != '.' && !(i == 0 && (c == '+' || c == '-')) {
+			break
+		}
+	}
+
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiple, ok := bytesUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in bytes value %q", unit, s)
+	}
+
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/multiple || n < math.MinInt64/multiple {
+			return 0, fmt.Errorf("bytes value %q out of range", s)
+		}
+		return n * multiple, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bytes value %q", s)
+	}
+	f *= float64(multiple)
+	if f >= math.MaxInt64 || f <= math.MinInt64 {
+		return 0, fmt.Errorf("bytes value %q out of range", s)
+	}
+	return int64(f), nil
+}
+
+// GetStringMapValue 获取 prefix. 下的所有配置项，key 为去除前缀后的剩余部分（如 redis.host => host），获取不到则取默认值
+// prefix 为空时返回全部配置项，值为嵌套 map 的配置项会被展开
+func (c *Config) GetStringMapValue(prefix string, defaultValue map[string]interface{}) map[string]interface{} {
+	m := subValues(c.getConfigValues(prefix, true), prefix)
+	if len(m) == 0 {
+		return defaultValue
+	}
+	return m
+}
+
+// GetSubConfig 获取 prefix. 下的所有配置项并按 . 组装为嵌套 map，如 redis.pool.size => {"pool": {"size": ...}}
+// 获取不到时返回 nil
+func (c *Config) GetSubConfig(prefix string) map[string]interface{} {
+	return c.nestValues(subValues(c.getConfigValues(prefix, true), prefix))
+}
+
+// getConfigValues 获取 key 等于 prefix 或以 prefix. 开头的配置项
+func (c *Config) getConfigValues(prefix string, waitInit bool) map[string]interface{} {
+	b := c.GetIsInit()
+	if !b {
+		if !waitInit {
+			c.getLogger().Log(log.LevelError, "get config values fail, init not done", log.KV("namespace", c.namespace), log.KV("prefix", prefix))
+			return nil
+		}
+		c.waitInit.Wait()
+	}
+	if c.cache == nil {
+		c.getLogger().Log(log.LevelError, "get config values fail, namespace not exist", log.KV("namespace", c.namespace))
+		return nil
+	}
+
+	values := make(map[string]interface{})
+	c.cache.Range(func(key, value interface{}) bool {
+		k, ok := key.(string)
+		if ok && (prefix == utils.Empty || k == prefix || strings.HasPrefix(k, prefix+keySeparator)) {
+			values[k] = value
+		}
+		return true
+	})
+	return values
+}
+
+// subValues 去除 key 中的 prefix. 前缀，并展开值为 map 的配置项
+func subValues(values map[string]interface{}, prefix string) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	m := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if prefix != utils.Empty {
+			if key == prefix {
+				// prefix 本身的值只有是 map 时才属于子配置
+				if child, ok := value.(map[string]interface{}); ok {
+					flattenMap(utils.Empty, child, m)
+				}
+				continue
+			}
+			key = key[len(prefix)+len(keySeparator):]
+		}
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenMap(key, child, m)
+			continue
+		}
+		m[key] = value
+	}
+	return m
+}
+
+// nestValues 将以 . 连接的 key 组装为嵌套 map，同一 key 既有值又有子配置项时保留子配置项
+func (c *Config) nestValues(values map[string]interface{}) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	root := make(map[string]interface{})
+	for key, value := range values {
+		segments := strings.Split(key, keySeparator)
+		node := root
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := node[segment].(map[string]interface{})
+			if !ok {
+				if _, exist := node[segment]; exist {
+					c.getLogger().Log(log.LevelDebug, "nest config fail, key conflicts with its children, value is dropped", log.KV("key", segment))
+				}
+				child = make(map[string]interface{})
+				node[segment] = child
+			}
+			node = child
+		}
+
+		last := segments[len(segments)-1]
+		if _, ok := node[last].(map[string]interface{}); ok {
+			c.getLogger().Log(log.LevelDebug, "nest config fail, key conflicts with its children, value is dropped", log.KV("key", key))
+			continue
+		}
+		node[last] = value
+	}
+	return root
+}
+
 // UpdateApolloConfig 根据config server返回的内容更新内存
 // 并判断是否需要写备份文件
 func (c *Cache) UpdateApolloConfig(apolloConfig *config.ApolloConfig, appConfigFunc func() config.AppConfig) {
 	if apolloConfig == nil {
-		log.Error("apolloConfig is null, can't update!")
+		c.getLogger().Log(log.LevelError, "apolloConfig is null, can't update")
 		return
 	}
 
 	appConfig := appConfigFunc()
+	ctx, span := extension.GetTracer().Start(context.Background(), tracing.SpanUpdateConfig,
+		tracing.String(tracing.AppID, appConfig.AppID),
+		tracing.String(tracing.Cluster, appConfig.Cluster),
+		tracing.String(tracing.Namespace, apolloConfig.NamespaceName),
+		tracing.String(tracing.ReleaseKey, apolloConfig.ReleaseKey))
+	defer span.End(nil)
+
 	// update apollo connection config
 	appConfig.SetCurrentApolloConfig(&apolloConfig.ApolloConnConfig)
+	extension.GetMetrics().SetReleaseKey(apolloConfig.NamespaceName, apolloConfig.ReleaseKey)
 
 	// get change list
 	changeList := c.UpdateApolloConfigCache(apolloConfig.Configurations, configCacheExpireTime, apolloConfig.NamespaceName)
+	namespaceConfig := c.GetConfig(apolloConfig.NamespaceName)
+	if namespaceConfig == nil {
+		// namespace 在更新期间被 RemoveNamespace 移除，丢弃本次更新
+		return
+	}
+	version := namespaceConfig.version.Add(1)
 
 	notify := appConfig.GetNotificationsMap().GetNotify(apolloConfig.NamespaceName)
 
 	// push all newest changes
-	c.pushNewestChanges(apolloConfig.NamespaceName, apolloConfig.Configurations, notify)
+	c.pushNewestChanges(ctx, apolloConfig.NamespaceName, apolloConfig.Configurations, notify, version)
 
 	if len(changeList) > 0 {
+		extension.GetMetrics().IncCacheUpdate(apolloConfig.NamespaceName)
 		// create config change event base on change list
 		event := createConfigChangeEvent(changeList, apolloConfig.NamespaceName, notify)
 
 		// push change event to channel
-		c.pushChangeEvent(event)
+		c.pushChangeEvent(ctx, event)
 	}
 
 	if appConfig.GetIsBackupConfig() {
 		// write config file async
 		apolloConfig.AppID = appConfig.AppID
-		go extension.GetFileHandler().WriteConfigFile(apolloConfig, appConfig.GetBackupConfigPath())
+		go c.getFileHandler().WriteConfigFile(apolloConfig, appConfig.GetBackupConfigPath())
 	}
 }
 
@@ -581,7 +901,8 @@ func (c *Cache) UpdateApolloConfig(apolloConfig *config.ApolloConfig, appConfigF
 func (c *Cache) UpdateApolloConfigCache(configurations map[string]interface{}, expireTime int, namespace string) map[string]*ConfigChange {
 	config := c.GetConfig(namespace)
 	if config == nil {
-		config = initConfig(namespace, extension.GetCacheFactory())
+		config = initConfig(namespace, c.getCacheFactory())
+		config.logger = c.logger
 		c.apolloConfigCache.Store(namespace, config)
 	}
 
@@ -627,7 +948,7 @@ func (c *Cache) UpdateApolloConfigCache(configurations map[string]interface{}, e
 		}
 
 		if err := config.cache.Set(key, value, expireTime); err != nil {
-			log.Errorf("set key %s to cache, error: %v", key, err)
+			c.getLogger().Log(log.LevelError, "set config to cache fail", log.KV("namespace", namespace), log.KV("key", key), log.KV("error", err))
 		}
 
 		delete(mp, key)
@@ -693,6 +1014,21 @@ func (c *Cache) RemoveChangeListener(listener ChangeListener) {
 	}
 }
 
+// RemoveNamespace 移除 namespace 的配置缓存，以及只监听该 namespace 的 NamespaceChangeListener
+func (c *Cache) RemoveNamespace(namespace string) {
+	c.apolloConfigCache.Delete(namespace)
+
+	c.rw.Lock()
+	defer c.rw.Unlock()
+	for i := c.changeListeners.Front(); i != nil; {
+		next := i.Next()
+		if listener, ok := i.Value.(NamespaceChangeListener); ok && listener.Namespace() == namespace {
+			c.changeListeners.Remove(i)
+		}
+		i = next
+	}
+}
+
 // GetChangeListeners 获取配置修改监听器列表
 func (c *Cache) GetChangeListeners() *list.List {
 	if c.changeListeners == nil {
@@ -706,24 +1042,26 @@ func (c *Cache) GetChangeListeners() *list.List {
 }
 
 // push config change event
-func (c *Cache) pushChangeEvent(event *ChangeEvent) {
-	c.pushChange(func(listener ChangeListener) {
-		go listener.OnChange(event)
+func (c *Cache) pushChangeEvent(ctx context.Context, event *ChangeEvent) {
+	c.pushChange(ctx, event.Namespace, func(listener ChangeListener) {
+		listener.OnChange(event)
 	})
 }
 
-func (c *Cache) pushNewestChanges(namespace string, configuration map[string]interface{}, notificationID int64) {
+func (c *Cache) pushNewestChanges(ctx context.Context, namespace string, configuration map[string]interface{}, notificationID int64, version uint64) {
 	e := &FullChangeEvent{
 		Changes: configuration,
+		version: version,
 	}
 	e.Namespace = namespace
 	e.NotificationID = notificationID
-	c.pushChange(func(listener ChangeListener) {
-		go listener.OnNewestChange(e)
+	c.pushChange(ctx, namespace, func(listener ChangeListener) {
+		listener.OnNewestChange(e)
 	})
 }
 
-func (c *Cache) pushChange(f func(ChangeListener)) {
+// pushChange 异步通知所有监听器，并记录每个监听器的处理耗时与 span
+func (c *Cache) pushChange(ctx context.Context, namespace string, f func(ChangeListener)) {
 	// if channel is null ,mean no listener,don't need to push msg
 	listeners := c.GetChangeListeners()
 	if listeners == nil || listeners.Len() == 0 {
@@ -732,6 +1070,14 @@ func (c *Cache) pushChange(f func(ChangeListener)) {
 
 	for i := listeners.Front(); i != nil; i = i.Next() {
 		listener := i.Value.(ChangeListener)
-		f(listener)
+		go func() {
+			_, span := extension.GetTracer().Start(ctx, tracing.SpanListenerDispatch,
+				tracing.String(tracing.Namespace, namespace),
+				tracing.String(tracing.Listener, fmt.Sprintf("%T", listener)))
+			startTime := time.Now()
+			f(listener)
+			extension.GetMetrics().ObserveListenerDispatch(namespace, time.Since(startTime))
+			span.End(nil)
+		}()
 	}
 }
@@ -15,7 +15,9 @@
 package storage
 
 import (
+	"context"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -23,11 +25,13 @@ import (
 
 	"github.com/apolloconfig/agollo/v5/agcache/memory"
 	_ "github.com/apolloconfig/agollo/v5/agcache/memory"
+	"github.com/apolloconfig/agollo/v5/component/log"
 	"github.com/apolloconfig/agollo/v5/env"
 	"github.com/apolloconfig/agollo/v5/env/config"
 	_ "github.com/apolloconfig/agollo/v5/env/file/json"
 	jsonFile "github.com/apolloconfig/agollo/v5/env/file/json"
 	"github.com/apolloconfig/agollo/v5/extension"
+	"github.com/apolloconfig/agollo/v5/tracing"
 	"github.com/apolloconfig/agollo/v5/utils"
 	_ "github.com/apolloconfig/agollo/v5/utils/parse/normal"
 	_ "github.com/apolloconfig/agollo/v5/utils/parse/properties"
@@ -208,7 +212,7 @@ func TestRegDispatchInRepository(t *testing.T) {
 	cEvent := createChangeEvent()
 	cache := CreateNamespaceConfig("abc")
 	cache.AddChangeListener(dispatch)
-	cache.pushChangeEvent(cEvent)
+	cache.pushChangeEvent(context.Background(), cEvent)
 	time.Sleep(1 * time.Second)
 	Assert(t, l.Len(), Equal(2))
 	v, ok := l.Value("add")
@@ -230,7 +234,7 @@ func TestDispatchInRepository(t *testing.T) {
 	cEvent := createChangeEvent()
 	cache := CreateNamespaceConfig("abc")
 	cache.AddChangeListener(dispatch)
-	cache.pushChangeEvent(cEvent)
+	cache.pushChangeEvent(context.Background(), cEvent)
 	time.Sleep(1 * time.Second)
 	Assert(t, l.Len(), Equal(2))
 	v, ok := l.Value("add")
@@ -409,3 +413,221 @@ func TestGetIntSliceValueFromInterfaceSlice(t *testing.T) {
 	slice = config.GetIntSliceValueImmediately("fractionalNumbers", []int{77})
 	Assert(t, slice, Equal([]int{77}))
 }
+
+func TestGetDurationValue(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["timeout"] = "1m30s"
+	configurations["nanos"] = 1000
+	configurations["bad"] = "abc"
+	configurations["float"] = 1.5
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	Assert(t, config.GetDurationValue("timeout", time.Second), Equal(90*time.Second))
+	Assert(t, config.GetDurationValue("nanos", time.Second), Equal(time.Microsecond))
+	Assert(t, config.GetDurationValue("bad", time.Second), Equal(time.Second))
+	Assert(t, config.GetDurationValue("float", time.Second), Equal(time.Second))
+	Assert(t, config.GetDurationValue("joe", time.Second), Equal(time.Second))
+
+	Assert(t, config.GetDurationValueImmediately("timeout", time.Second), Equal(90*time.Second))
+}
+
+func TestGetTimeValue(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["date"] = "2021-07-01"
+	configurations["rfc3339"] = "2021-07-01T08:00:00+08:00"
+	configurations["int"] = 1
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	defaultValue := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	Assert(t, config.GetTimeValue("date", "2006-01-02", defaultValue), Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+	Assert(t, config.GetTimeValue("rfc3339", time.RFC3339, defaultValue).Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)), Equal(true))
+	Assert(t, config.GetTimeValue("date", time.RFC3339, defaultValue), Equal(defaultValue))
+	Assert(t, config.GetTimeValue("int", time.RFC3339, defaultValue), Equal(defaultValue))
+	Assert(t, config.GetTimeValue("joe", time.RFC3339, defaultValue), Equal(defaultValue))
+
+	Assert(t, config.GetTimeValueImmediately("date", "2006-01-02", defaultValue), Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestGetBytesValue(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["plain"] = "1024"
+	configurations["kb"] = "4KB"
+	configurations["mib"] = "64 MiB"
+	configurations["lower"] = "1g"
+	configurations["fraction"] = "1.5K"
+	configurations["int"] = 512
+	configurations["float"] = float64(256)
+	configurations["unknown"] = "10XB"
+	configurations["overflow"] = "9000000PB"
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	Assert(t, config.GetBytesValue("plain", 1), Equal(int64(1024)))
+	Assert(t, config.GetBytesValue("kb", 1), Equal(int64(4<<10)))
+	Assert(t, config.GetBytesValue("mib", 1), Equal(int64(64<<20)))
+	Assert(t, config.GetBytesValue("lower", 1), Equal(int64(1<<30)))
+	Assert(t, config.GetBytesValue("fraction", 1), Equal(int64(1536)))
+	Assert(t, config.GetBytesValue("int", 1), Equal(int64(512)))
+	Assert(t, config.GetBytesValue("float", 1), Equal(int64(256)))
+	Assert(t, config.GetBytesValue("unknown", 1), Equal(int64(1)))
+	Assert(t, config.GetBytesValue("overflow", 1), Equal(int64(1)))
+	Assert(t, config.GetBytesValue("joe", 1), Equal(int64(1)))
+
+	Assert(t, config.GetBytesValueImmediately("kb", 1), Equal(int64(4<<10)))
+}
+
+func TestGetStringMapValue(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["redis.host"] = "127.0.0.1"
+	configurations["redis.port"] = 6379
+	configurations["redis.pool.size"] = 10
+	configurations["redisCluster"] = "no"
+	configurations["mysql"] = map[string]interface{}{"host": "localhost"}
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	Assert(t, config.GetStringMapValue("redis", nil), Equal(map[string]interface{}{
+		"host":      "127.0.0.1",
+		"port":      6379,
+		"pool.size": 10,
+	}))
+	Assert(t, config.GetStringMapValue("redis.pool", nil), Equal(map[string]interface{}{"size": 10}))
+	Assert(t, config.GetStringMapValue("mysql", nil), Equal(map[string]interface{}{"host": "localhost"}))
+	Assert(t, len(config.GetStringMapValue("", nil)), Equal(5))
+
+	defaultValue := map[string]interface{}{"a": "b"}
+	Assert(t, config.GetStringMapValue("joe", defaultValue), Equal(defaultValue))
+	Assert(t, config.GetStringMapValue("redis.host", defaultValue), Equal(defaultValue))
+
+	Assert(t, config.GetStringMapValueImmediately("redis.pool", nil), Equal(map[string]interface{}{"size": 10}))
+}
+
+func TestGetSubConfig(t *testing.T) {
+	configurations := make(map[string]interface{})
+	configurations["redis.host"] = "127.0.0.1"
+	configurations["redis.pool.size"] = 10
+	configurations["redis.pool.idle"] = 2
+	configurations["redis.nodes"] = []interface{}{"a", "b"}
+	configurations["redis.conflict"] = "leaf"
+	configurations["redis.conflict.child"] = "child"
+
+	c := creatTestApolloConfig(configurations, "test")
+	config := c.GetConfig("test")
+	Assert(t, config, NotNilVal())
+
+	Assert(t, config.GetSubConfig("redis"), Equal(map[string]interface{}{
+		"host": "127.0.0.1",
+		"pool": map[string]interface{}{
+			"size": 10,
+			"idle": 2,
+		},
+		"nodes":    []interface{}{"a", "b"},
+		"conflict": map[string]interface{}{"child": "child"},
+	}))
+	Assert(t, config.GetSubConfig("redis.pool"), Equal(map[string]interface{}{"size": 10, "idle": 2}))
+	Assert(t, config.GetSubConfig("joe") == nil, Equal(true))
+
+	Assert(t, config.GetSubConfigImmediately("redis.pool"), Equal(map[string]interface{}{"size": 10, "idle": 2}))
+}
+
+func TestUpdateApolloConfigTracing(t *testing.T) {
+	tracer := &recordTracer{}
+	extension.SetTracer(tracer)
+	defer extension.SetTracer(nil)
+
+	c := CreateNamespaceConfig("tracing")
+	l := &CustomChangeListener{}
+	l.w.Add(1)
+	c.AddChangeListener(l)
+	appConfig := env.InitFileConfig()
+	apolloConfig := &config.ApolloConfig{}
+	apolloConfig.NamespaceName = "tracing"
+	apolloConfig.AppID = "test"
+	apolloConfig.ReleaseKey = "20250101"
+	apolloConfig.Configurations = map[string]interface{}{"key": "value"}
+	c.UpdateApolloConfig(apolloConfig, func() config.AppConfig {
+		return *appConfig
+	})
+	l.w.Wait()
+
+	// 两个监听器回调 span 在回调返回后异步结束
+	for i := 0; i < 100 && len(tracer.getSpans()) < 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	names := make(map[string]int)
+	for _, span := range tracer.getSpans() {
+		names[span.name]++
+		if span.name == tracing.SpanUpdateConfig {
+			Assert(t, span.attrs[len(span.attrs)-1], Equal(tracing.String(tracing.ReleaseKey, "20250101")))
+		}
+	}
+	Assert(t, names[tracing.SpanUpdateConfig], Equal(1))
+	Assert(t, names[tracing.SpanListenerDispatch], Equal(2))
+}
+
+type recordTracer struct {
+	lock  sync.Mutex
+	spans []*recordSpan
+}
+
+func (r *recordTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	span := &recordSpan{tracer: r, name: name, attrs: attrs}
+	return ctx, span
+}
+
+func (r *recordTracer) getSpans() []*recordSpan {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*recordSpan(nil), r.spans...)
+}
+
+type recordSpan struct {
+	tracer *recordTracer
+	name   string
+	attrs  []tracing.Attribute
+}
+
+func (s *recordSpan) SetAttributes(attrs ...tracing.Attribute) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+func (s *recordSpan) End(err error) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
+type testStructuredLogger struct {
+	msgs []string
+}
+
+func (l *testStructuredLogger) Enabled(level log.Level) bool {
+	return true
+}
+
+func (l *testStructuredLogger) Log(level log.Level, msg string, fields ...log.Field) {
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestCacheSetLogger(t *testing.T) {
+	c := CreateNamespaceConfig("logger")
+	logger := &testStructuredLogger{}
+	c.SetLogger(logger)
+
+	Assert(t, c.GetConfig("logger").GetValueImmediately("key"), Equal(utils.Empty))
+	c.UpdateApolloConfigCache(map[string]interface{}{"key": "value"}, configCacheExpireTime, "logger-new")
+	Assert(t, c.GetConfig("logger-new").GetIntValue("key", 1), Equal(1))
+	Assert(t, logger.msgs, Equal([]string{"get config value fail, init not done", "Atoi fail"}))
+}
@@ -0,0 +1,590 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+)
+
+const (
+	tagName        = "apollo"
+	defaultTagName = "default"
+	requiredOption = "required"
+	keySeparator   = "."
+	sliceSeparator = ","
+	// maxIndexedSliceGap key.0、key.1 ... 形式的切片允许缺失的下标数量，避免过大的下标分配过多内存
+	maxIndexedSliceGap = 1024
+)
+
+var (
+	// ErrRequiredKeyNotFound 必填的配置项不存在且没有默认值
+	ErrRequiredKeyNotFound = errors.New("required key not found")
+
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Unmarshal 将 namespace 的配置解析到 v 中，v 必须为非 nil 的结构体指针
+//
+// 字段通过 apollo:"key" 指定配置项，嵌套结构体的 key 以 . 拼接，未指定时按字段名不区分大小写匹配，apollo:"-" 忽略该字段；
+// apollo:"key,required" 表示配置项必须存在，default:"value" 指定配置项不存在时使用的值；
+// 切片支持数组值、逗号分隔的字符串以及 key.0、key.1 形式的下标 key，map 由 key. 前缀下的所有配置项组成；
+// time.Duration 使用 time.ParseDuration 解析，time.Time 使用 RFC3339 格式解析
+func (c *Config) Unmarshal(v interface{}) error {
+	if !c.GetIsInit() {
+		c.waitInit.Wait()
+	}
+	if c.cache == nil {
+		return fmt.Errorf("unmarshal fail! namespace:%s not exist", c.namespace)
+	}
+
+	values := make(map[string]interface{})
+	c.cache.Range(func(key, value interface{}) bool {
+		values[key.(string)] = value
+		return true
+	})
+	return unmarshal(values, v)
+}
+
+func unmarshal(values map[string]interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal fail! target must be a non-nil pointer to struct, got %T", v)
+	}
+	_, err := newDecoder(values).decodeStruct(utils.Empty, rv.Elem())
+	return err
+}
+
+// decoder 将展开后的配置项解析到结构体中
+type decoder struct {
+	values map[string]interface{}
+	// lower 小写 key => 原始 key，用于不区分大小写匹配
+	lower map[string]string
+	// embedding 当前路径上正在解析的匿名结构体指针类型，避免自引用的匿名字段无限递归
+	embedding map[reflect.Type]bool
+}
+
+func newDecoder(values map[string]interface{}) *decoder {
+	d := &decoder{
+		values:    values,
+		lower:     make(map[string]string, len(values)),
+		embedding: make(map[reflect.Type]bool),
+	}
+	for key := range values {
+		d.lower[strings.ToLower(key)] = key
+	}
+	return d
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == utils.Empty {
+		return key
+	}
+	return prefix + keySeparator + key
+}
+
+func (d *decoder) lookup(key string) (interface{}, bool) {
+	if value, ok := d.values[key]; ok {
+		return value, true
+	}
+	if original, ok := d.lower[strings.ToLower(key)]; ok {
+		return d.values[original], true
+	}
+	return nil, false
+}
+
+// hasKey 判断是否存在 key 或 key. 下的配置项
+func (d *decoder) hasKey(key string) bool {
+	if _, ok := d.lookup(key); ok {
+		return true
+	}
+	p := strings.ToLower(key) + keySeparator
+	for lowerKey := range d.lower {
+		if strings.HasPrefix(lowerKey, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// children 返回 prefix. 下所有配置项，key 为去除前缀后的剩余部分
+func (d *decoder) children(prefix string) map[string]interface{} {
+	p := strings.ToLower(prefix) + keySeparator
+	m := make(map[string]interface{})
+	for lowerKey, key := range d.lower {
+		if strings.HasPrefix(lowerKey, p) {
+			m[key[len(p):]] = d.values[key]
+		}
+	}
+	return m
+}
+
+func (d *decoder) decodeStruct(prefix string, rv reflect.Value) (bool, error) {
+	found := false
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, required := parseTag(field.Tag.Get(tagName))
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		// 未指定 key 的匿名结构体与外层共用前缀
+		if field.Anonymous && name == utils.Empty && isEmbeddedStruct(field) {
+			embedded, err := d.decodeField(prefix, fv, true)
+			if err != nil {
+				return found, err
+			}
+			found = found || embedded
+			continue
+		}
+		if field.PkgPath != utils.Empty {
+			continue
+		}
+
+		if name == utils.Empty {
+			name = field.Name
+		}
+		key := joinKey(prefix, name)
+		ok, err := d.decodeField(key, fv, false)
+		if err != nil {
+			return found, err
+		}
+		if ok {
+			found = true
+			continue
+		}
+
+		// 默认值不计入 found，避免仅因默认值而创建指针字段
+		if defaultValue, has := field.Tag.Lookup(defaultTagName); has {
+			if err := setValue(key, fv, defaultValue); err != nil {
+				return found, err
+			}
+		} else if required {
+			return found, fmt.Errorf("%w: %s", ErrRequiredKeyNotFound, key)
+		}
+	}
+	return found, nil
+}
+
+// isEmbeddedStruct 匿名字段为可设置的结构体或结构体指针
+func isEmbeddedStruct(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		// 未导出的匿名指针无法赋值
+		if field.PkgPath != utils.Empty {
+			return false
+		}
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+func parseTag(tag string) (string, bool) {
+	parts := strings.Split(tag, ",")
+	required := false
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == requiredOption {
+			required = true
+		}
+	}
+	return strings.TrimSpace(parts[0]), required
+}
+
+// decodeField 解析 key 对应的配置到 fv，返回是否找到了配置
+func (d *decoder) decodeField(key string, fv reflect.Value, embedded bool) (bool, error) {
+	t := fv.Type()
+	if t.Kind() == reflect.Ptr {
+		// 只在存在对应配置项时创建结构体指针，避免自引用的结构体无限递归
+		if embedded {
+			if d.embedding[t.Elem()] {
+				return false, nil
+			}
+			d.embedding[t.Elem()] = true
+			defer delete(d.embedding, t.Elem())
+		} else if t.Elem().Kind() == reflect.Struct && !d.hasKey(key) {
+			return false, nil
+		}
+		elem := reflect.New(t.Elem())
+		found, err := d.decodeField(key, elem.Elem(), embedded)
+		if found && err == nil {
+			fv.Set(elem)
+		}
+		return found, err
+	}
+
+	if embedded {
+		return d.decodeStruct(key, fv)
+	}
+
+	value, ok := d.lookup(key)
+	switch {
+	case t == durationType || t == timeType:
+	case t.Kind() == reflect.Struct:
+		if ok {
+			if _, isMap := value.(map[string]interface{}); isMap {
+				return true, setValue(key, fv, value)
+			}
+		}
+		return d.decodeStruct(key, fv)
+	case t.Kind() == reflect.Map:
+		if ok {
+			return true, setValue(key, fv, value)
+		}
+		return d.decodeMap(key, fv)
+	case t.Kind() == reflect.Slice:
+		if ok {
+			return true, setValue(key, fv, value)
+		}
+		return d.decodeIndexedSlice(key, fv)
+	}
+
+	if !ok {
+		return false, nil
+	}
+	return true, setValue(key, fv, value)
+}
+
+// decodeMap 使用 key. 前缀下的配置项构造 map
+func (d *decoder) decodeMap(key string, fv reflect.Value) (bool, error) {
+	t := fv.Type()
+	if t.Key().Kind() != reflect.String {
+		return false, fmt.Errorf("unmarshal fail! key:%s, map key type must be string, got %s", key, t.Key())
+	}
+
+	children := d.children(key)
+	if len(children) == 0 {
+		return false, nil
+	}
+
+	m := reflect.MakeMapWithSize(t, len(children))
+	elemType := t.Elem()
+	if elemType.Kind() == reflect.Struct && elemType != timeType && elemType != durationType {
+		// 结构体按第一段 key 分组
+		for name := range groupByFirstSegment(children) {
+			elem := reflect.New(elemType).Elem()
+			if _, err := d.decodeStruct(joinKey(key, name), elem); err != nil {
+				return true, err
+			}
+			m.SetMapIndex(reflect.ValueOf(name).Convert(t.Key()), elem)
+		}
+	} else {
+		for name, value := range children {
+			elem := reflect.New(elemType).Elem()
+			if err := setValue(joinKey(key, name), elem, value); err != nil {
+				return true, err
+			}
+			m.SetMapIndex(reflect.ValueOf(name).Convert(t.Key()), elem)
+		}
+	}
+	fv.Set(m)
+	return true, nil
+}
+
+func groupByFirstSegment(children map[string]interface{}) map[string]bool {
+	names := make(map[string]bool, len(children))
+	for key := range children {
+		names[strings.SplitN(key, keySeparator, 2)[0]] = true
+	}
+	return names
+}
+
+// decodeIndexedSlice 使用 key.0、key.1 ... 形式的配置项构造切片
+func (d *decoder) decodeIndexedSlice(key string, fv reflect.Value) (bool, error) {
+	indexes := make([]int, 0)
+	for name := range groupByFirstSegment(d.children(key)) {
+		if i, err := strconv.Atoi(name); err == nil && i >= 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return false, nil
+	}
+	sort.Ints(indexes)
+
+	maxIndex := indexes[len(indexes)-1]
+	if maxIndex >= len(indexes)+maxIndexedSliceGap {
+		return true, fmt.Errorf("unmarshal fail! key:%s, slice index %d is too large for %d items", key, maxIndex, len(indexes))
+	}
+	s := reflect.MakeSlice(fv.Type(), maxIndex+1, maxIndex+1)
+	for _, i := range indexes {
+		if _, err := d.decodeField(joinKey(key, strconv.Itoa(i)), s.Index(i), false); err != nil {
+			return true, err
+		}
+	}
+	fv.Set(s)
+	return true, nil
+}
+
+// setValue 将单个配置值转换后写入 fv
+func setValue(key string, fv reflect.Value, value interface{}) error {
+	t := fv.Type()
+	if value == nil {
+		fv.Set(reflect.Zero(t))
+		return nil
+	}
+
+	switch {
+	case t.Kind() == reflect.Ptr:
+		elem := reflect.New(t.Elem())
+		if err := setValue(key, elem.Elem(), value); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	case t == timeType || t == durationType:
+	case t.Kind() == reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return convertError(key, value, t, nil)
+		}
+		flat := make(map[string]interface{})
+		flattenMap(utils.Empty, m, flat)
+		_, err := newDecoder(flat).decodeStruct(utils.Empty, fv)
+		return err
+	case t.Kind() == reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return convertError(key, value, t, nil)
+		}
+		flat := make(map[string]interface{})
+		flattenMap(utils.Empty, m, flat)
+		if len(flat) == 0 {
+			fv.Set(reflect.MakeMap(t))
+			return nil
+		}
+		_, err := newDecoder(flat).decodeMap(utils.Empty, fv)
+		return err
+	case t.Kind() == reflect.Slice:
+		items, err := toSlice(key, value, t)
+		if err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := setValue(fmt.Sprintf("%s[%d]", key, i), s.Index(i), item); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+		return nil
+	}
+
+	converted, err := convertValue(value, t)
+	if err != nil {
+		return convertError(key, value, t, err)
+	}
+	fv.Set(converted)
+	return nil
+}
+
+func toSlice(key string, value interface{}, t reflect.Type) ([]interface{}, error) {
+	if s, ok := value.(string); ok {
+		if s == utils.Empty {
+			return []interface{}{}, nil
+		}
+		parts := strings.Split(s, sliceSeparator)
+		items := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			items = append(items, strings.TrimSpace(part))
+		}
+		return items, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, convertError(key, value, t, nil)
+	}
+	items := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		items = append(items, rv.Index(i).Interface())
+	}
+	return items, nil
+}
+
+// flattenMap 将嵌套的 map 展开为以 . 连接的 key
+func flattenMap(prefix string, m map[string]interface{}, out map[string]interface{}) {
+	for key, value := range m {
+		key = joinKey(prefix, key)
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenMap(key, child, out)
+			continue
+		}
+		out[key] = value
+	}
+}
+
+func convertError(key string, value interface{}, t reflect.Type, err error) error {
+	if err != nil {
+		return fmt.Errorf("unmarshal fail! key:%s, convert %T to %s fail, error:%v", key, value, t, err)
+	}
+	return fmt.Errorf("unmarshal fail! key:%s, convert %T to %s fail", key, value, t)
+}
+
+// convertValue 将配置值转换为 t 类型的标量
+func convertValue(value interface{}, t reflect.Type) (reflect.Value, error) {
+	rv := reflect.New(t).Elem()
+	switch {
+	case t == durationType:
+		switch v := value.(type) {
+		case string:
+			d, err := time.ParseDuration(strings.TrimSpace(v))
+			if err != nil {
+				return rv, err
+			}
+			rv.SetInt(int64(d))
+			return rv, nil
+		case time.Duration:
+			rv.SetInt(int64(v))
+			return rv, nil
+		}
+		i, err := toInt64(value)
+		if err != nil {
+			return rv, err
+		}
+		rv.SetInt(i)
+		return rv, nil
+	case t == timeType:
+		switch v := value.(type) {
+		case time.Time:
+			rv.Set(reflect.ValueOf(v))
+			return rv, nil
+		case string:
+			tm, err := time.Parse(time.RFC3339, strings.TrimSpace(v))
+			if err != nil {
+				return rv, err
+			}
+			rv.Set(reflect.ValueOf(tm))
+			return rv, nil
+		}
+		return rv, errors.New("unsupported type")
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(t) {
+			return rv, errors.New("unsupported type")
+		}
+		rv.Set(v)
+	case reflect.String:
+		if s, ok := value.(string); ok {
+			rv.SetString(s)
+		} else {
+			rv.SetString(fmt.Sprintf("%v", value))
+		}
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			rv.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return rv, err
+			}
+			rv.SetBool(b)
+		default:
+			return rv, errors.New("unsupported type")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(value)
+		if err != nil {
+			return rv, err
+		}
+		if rv.OverflowInt(i) {
+			return rv, errors.New("value out of range")
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := toInt64(value)
+		if err != nil {
+			if s, ok := value.(string); ok {
+				u, uErr := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+				if uErr != nil {
+					return rv, uErr
+				}
+				if rv.OverflowUint(u) {
+					return rv, errors.New("value out of range")
+				}
+				rv.SetUint(u)
+				return rv, nil
+			}
+			return rv, err
+		}
+		if i < 0 || rv.OverflowUint(uint64(i)) {
+			return rv, errors.New("value out of range")
+		}
+		rv.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(value)
+		if err != nil {
+			return rv, err
+		}
+		if rv.OverflowFloat(f) {
+			return rv, errors.New("value out of range")
+		}
+		rv.SetFloat(f)
+	default:
+		return rv, errors.New("unsupported type")
+	}
+	return rv, nil
+}
+
+func toInt64(value interface{}) (int64, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := rv.Uint()
+		if u > uint64(1<<63-1) {
+			return 0, errors.New("value out of range")
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != float64(int64(f)) {
+			return 0, fmt.Errorf("float64 value has fractional part: %v", f)
+		}
+		return int64(f), nil
+	case reflect.String:
+		return strconv.ParseInt(strings.TrimSpace(rv.String()), 10, 64)
+	}
+	return 0, errors.New("unsupported type")
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+	}
+	return 0, errors.New("unsupported type")
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+)
+
+type testDBConfig struct {
+	Host    string        `apollo:"host"`
+	Port    int           `apollo:"port" default:"3306"`
+	Timeout time.Duration `apollo:"timeout" default:"3s"`
+}
+
+type testBaseConfig struct {
+	Name string `apollo:"name,required"`
+}
+
+type testAppConfig struct {
+	testBaseConfig
+	Enabled  bool                    `apollo:"enabled"`
+	Ratio    float64                 `apollo:"ratio"`
+	Primary  testDBConfig            `apollo:"db.primary"`
+	Replica  *testDBConfig           `apollo:"db.replica"`
+	Backup   *testDBConfig           `apollo:"db.backup"`
+	Tags     []string                `apollo:"tags"`
+	Ports    []int                   `apollo:"ports"`
+	Servers  []testDBConfig          `apollo:"servers"`
+	Labels   map[string]string       `apollo:"labels"`
+	Clusters map[string]testDBConfig `apollo:"clusters"`
+	Start    time.Time               `apollo:"start"`
+	Retries  uint8
+	Ignored  string `apollo:"-"`
+	ignored  string
+}
+
+func TestConfigUnmarshal(t *testing.T) {
+	c := creatTestApolloConfig(map[string]interface{}{
+		"name":              "agollo",
+		"enabled":           "true",
+		"ratio":             0.5,
+		"db.primary.host":   "127.0.0.1",
+		"db.primary.port":   3307,
+		"db.replica.host":   "127.0.0.2",
+		"tags":              "a, b",
+		"ports":             []interface{}{8001, "8002"},
+		"servers":           []interface{}{map[string]interface{}{"host": "s1", "port": 1}},
+		"labels.zone":       "sh",
+		"labels.env.name":   "dev",
+		"clusters.a.host":   "a-host",
+		"clusters.b.port":   "1",
+		"start":             "2026-01-02T03:04:05Z",
+		"RETRIES":           "3",
+		"Ignored":           "x",
+		"ignored":           "x",
+		"db.primary.unused": "x",
+	}, "unmarshal")
+
+	var cfg testAppConfig
+	err := c.GetConfig("unmarshal").Unmarshal(&cfg)
+	Assert(t, err, NilVal())
+
+	Assert(t, cfg.Name, Equal("agollo"))
+	Assert(t, cfg.Enabled, Equal(true))
+	Assert(t, cfg.Ratio, Equal(0.5))
+	Assert(t, cfg.Primary, Equal(testDBConfig{Host: "127.0.0.1", Port: 3307, Timeout: 3 * time.Second}))
+	Assert(t, *cfg.Replica, Equal(testDBConfig{Host: "127.0.0.2", Port: 3306, Timeout: 3 * time.Second}))
+	Assert(t, cfg.Backup, NilVal())
+	Assert(t, cfg.Tags, Equal([]string{"a", "b"}))
+	Assert(t, cfg.Ports, Equal([]int{8001, 8002}))
+	Assert(t, cfg.Servers, Equal([]testDBConfig{{Host: "s1", Port: 1, Timeout: 3 * time.Second}}))
+	Assert(t, cfg.Labels, Equal(map[string]string{"zone": "sh", "env.name": "dev"}))
+	Assert(t, cfg.Clusters["a"], Equal(testDBConfig{Host: "a-host", Port: 3306, Timeout: 3 * time.Second}))
+	Assert(t, cfg.Clusters["b"], Equal(testDBConfig{Port: 1, Timeout: 3 * time.Second}))
+	Assert(t, cfg.Start, Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+	Assert(t, cfg.Retries, Equal(uint8(3)))
+	Assert(t, cfg.Ignored, Equal(""))
+	Assert(t, cfg.ignored, Equal(""))
+}
+
+func TestConfigUnmarshalIndexedSlice(t *testing.T) {
+	c := creatTestApolloConfig(map[string]interface{}{
+		"servers.server.0.host": "a",
+		"servers.server.1.host": "b",
+		"servers.server.1.port": "81",
+	}, "unmarshal-indexed")
+
+	var cfg struct {
+		Servers []testDBConfig `apollo:"servers.server"`
+	}
+	err := c.GetConfig("unmarshal-indexed").Unmarshal(&cfg)
+	Assert(t, err, NilVal())
+	Assert(t, len(cfg.Servers), Equal(2))
+	Assert(t, cfg.Servers[0].Host, Equal("a"))
+	Assert(t, cfg.Servers[1].Port, Equal(81))
+
+	c = creatTestApolloConfig(map[string]interface{}{
+		"servers.server.0.host":         "a",
+		"servers.server.999999999.host": "b",
+	}, "unmarshal-indexed-large")
+	err = c.GetConfig("unmarshal-indexed-large").Unmarshal(&cfg)
+	Assert(t, err, NotNilVal())
+}
+
+type testNode struct {
+	Name string    `apollo:"name"`
+	Next *testNode `apollo:"next"`
+	*testNode
+}
+
+func TestConfigUnmarshalSelfReference(t *testing.T) {
+	c := creatTestApolloConfig(map[string]interface{}{
+		"name":           "a",
+		"next.name":      "b",
+		"next.next.name": "c",
+	}, "unmarshal-self-reference")
+
+	var node testNode
+	err := c.GetConfig("unmarshal-self-reference").Unmarshal(&node)
+	Assert(t, err, NilVal())
+	Assert(t, node.Name, Equal("a"))
+	Assert(t, node.Next.Name, Equal("b"))
+	Assert(t, node.Next.Next.Name, Equal("c"))
+	Assert(t, node.Next.Next.Next == nil, Equal(true))
+}
+
+func TestConfigUnmarshalError(t *testing.T) {
+	c := creatTestApolloConfig(map[string]interface{}{
+		"port":    "abc",
+		"timeout": "3 parsecs",
+		"small":   300,
+		"count":   1.5,
+	}, "unmarshal-error")
+	cfg := c.GetConfig("unmarshal-error")
+
+	var required testBaseConfig
+	err := cfg.Unmarshal(&required)
+	Assert(t, errors.Is(err, ErrRequiredKeyNotFound), Equal(true))
+
+	var port struct {
+		Port int `apollo:"port"`
+	}
+	Assert(t, cfg.Unmarshal(&port), NotNilVal())
+
+	var timeout struct {
+		Timeout time.Duration `apollo:"timeout"`
+	}
+	Assert(t, cfg.Unmarshal(&timeout), NotNilVal())
+
+	var small struct {
+		Small int8 `apollo:"small"`
+	}
+	Assert(t, cfg.Unmarshal(&small), NotNilVal())
+
+	var count struct {
+		Count int `apollo:"count"`
+	}
+	Assert(t, cfg.Unmarshal(&count), NotNilVal())
+
+	Assert(t, cfg.Unmarshal(nil), NotNilVal())
+	Assert(t, cfg.Unmarshal(port), NotNilVal())
+	var s string
+	Assert(t, cfg.Unmarshal(&s), NotNilVal())
+}
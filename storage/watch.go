@@ -0,0 +1,109 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apolloconfig/agollo/v5/component/log"
+)
+
+// WatchSource 可被 Watch 的配置来源，Cache 与 agollo.Client 均满足该接口
+type WatchSource interface {
+	GetConfig(namespace string) *Config
+	AddChangeListener(listener ChangeListener)
+	RemoveChangeListener(listener ChangeListener)
+}
+
+// Watcher 持有 namespace 配置解析后的 T 类型快照
+// 每次 UpdateApolloConfig 更新该 namespace 后重新解析，成功后原子替换快照，解析失败时保留旧快照
+type Watcher[T any] struct {
+	source    WatchSource
+	namespace string
+	value     atomic.Pointer[T]
+	// mu 保证快照按 version 顺序替换
+	mu      sync.Mutex
+	version uint64
+	logger  log.StructuredLogger
+}
+
+// Watch 解析 namespace 当前配置生成初始快照，并监听后续更新，解析规则见 Config.Unmarshal
+func Watch[T any](source WatchSource, namespace string) (*Watcher[T], error) {
+	config := source.GetConfig(namespace)
+	if config == nil {
+		return nil, fmt.Errorf("watch fail! namespace:%s not found", namespace)
+	}
+
+	w := &Watcher[T]{
+		source:    source,
+		namespace: namespace,
+		logger:    config.getLogger(),
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// 先注册监听再读取当前配置，避免遗漏两者之间的更新
+	source.AddChangeListener(w)
+	w.version = config.version.Load()
+
+	var v T
+	if err := config.Unmarshal(&v); err != nil {
+		source.RemoveChangeListener(w)
+		return nil, err
+	}
+	w.value.Store(&v)
+	return w, nil
+}
+
+// Load 获取当前快照，无锁
+func (w *Watcher[T]) Load() T {
+	return *w.value.Load()
+}
+
+// Close 停止监听配置更新，快照保持不变
+func (w *Watcher[T]) Close() {
+	w.source.RemoveChangeListener(w)
+}
+
+// Namespace 获取监听的 namespace
+func (w *Watcher[T]) Namespace() string {
+	return w.namespace
+}
+
+// OnChange 增加变更监控
+func (w *Watcher[T]) OnChange(event *ChangeEvent) {
+}
+
+// OnNewestChange 使用更新后的完整配置重新解析快照
+func (w *Watcher[T]) OnNewestChange(event *FullChangeEvent) {
+	if event == nil || event.Namespace != w.namespace {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if event.version <= w.version {
+		return
+	}
+	w.version = event.version
+
+	var v T
+	if err := unmarshal(event.Changes, &v); err != nil {
+		w.logger.Log(log.LevelError, "watch refresh fail", log.KV("namespace", w.namespace), log.KV("error", err))
+		return
+	}
+	w.value.Store(&v)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/env"
+	"github.com/apolloconfig/agollo/v5/env/config"
+)
+
+type testWatchConfig struct {
+	Host string `apollo:"db.host,required"`
+	Port int    `apollo:"db.port" default:"3306"`
+}
+
+func updateTestApolloConfig(c *Cache, configurations map[string]interface{}, namespace string) {
+	appConfig := env.InitFileConfig()
+	apolloConfig := &config.ApolloConfig{}
+	apolloConfig.NamespaceName = namespace
+	apolloConfig.AppID = "test"
+	apolloConfig.Cluster = "dev"
+	apolloConfig.Configurations = configurations
+	c.UpdateApolloConfig(apolloConfig, func() config.AppConfig {
+		return *appConfig
+	})
+}
+
+func waitWatchValue(w *Watcher[testWatchConfig], expect testWatchConfig) testWatchConfig {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if w.Load() == expect {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return w.Load()
+}
+
+func TestWatch(t *testing.T) {
+	namespace := "watch"
+	c := creatTestApolloConfig(map[string]interface{}{
+		"db.host": "127.0.0.1",
+	}, namespace)
+
+	w, err := Watch[testWatchConfig](c, namespace)
+	Assert(t, err, NilVal())
+	defer w.Close()
+	Assert(t, w.Load(), Equal(testWatchConfig{Host: "127.0.0.1", Port: 3306}))
+
+	updateTestApolloConfig(c, map[string]interface{}{
+		"db.host": "127.0.0.2",
+		"db.port": "3307",
+	}, namespace)
+	expect := testWatchConfig{Host: "127.0.0.2", Port: 3307}
+	Assert(t, waitWatchValue(w, expect), Equal(expect))
+
+	// 解析失败时保留旧快照
+	updateTestApolloConfig(c, map[string]interface{}{
+		"db.port": "3308",
+	}, namespace)
+	time.Sleep(100 * time.Millisecond)
+	Assert(t, w.Load(), Equal(expect))
+
+	// 乱序到达的旧事件被丢弃
+	stale := &FullChangeEvent{
+		Changes: map[string]interface{}{"db.host": "stale"},
+		version: 1,
+	}
+	stale.Namespace = namespace
+	w.OnNewestChange(stale)
+	Assert(t, w.Load(), Equal(expect))
+
+	w.Close()
+	updateTestApolloConfig(c, map[string]interface{}{
+		"db.host": "127.0.0.3",
+	}, namespace)
+	time.Sleep(100 * time.Millisecond)
+	Assert(t, w.Load(), Equal(expect))
+}
+
+func TestWatchError(t *testing.T) {
+	namespace := "watch-error"
+	c := creatTestApolloConfig(map[string]interface{}{
+		"db.port": "3307",
+	}, namespace)
+
+	w, err := Watch[testWatchConfig](c, namespace)
+	Assert(t, err, NotNilVal())
+	Assert(t, w, NilVal())
+	Assert(t, c.GetChangeListeners().Len(), Equal(0))
+
+	w, err = Watch[testWatchConfig](c, "not-exist")
+	Assert(t, err, NotNilVal())
+	Assert(t, w, NilVal())
+}
+
+func TestRemoveNamespace(t *testing.T) {
+	c := CreateNamespaceConfig("application,db")
+	updateTestApolloConfig(c, map[string]interface{}{"db.host": "127.0.0.1"}, "db")
+	updateTestApolloConfig(c, map[string]interface{}{"key": "value"}, "application")
+
+	w, err := Watch[testWatchConfig](c, "db")
+	Assert(t, err, NilVal())
+	Assert(t, w.Namespace(), Equal("db"))
+	other, err := Watch[struct {
+		Key string `apollo:"key"`
+	}](c, "application")
+	Assert(t, err, NilVal())
+	listener := &CustomChangeListener{}
+	c.AddChangeListener(listener)
+	Assert(t, c.GetChangeListeners().Len(), Equal(3))
+
+	c.RemoveNamespace("db")
+	Assert(t, c.GetConfig("db"), NilVal())
+	Assert(t, c.GetConfig("application"), NotNilVal())
+	listeners := c.GetChangeListeners()
+	Assert(t, listeners.Len(), Equal(2))
+	Assert(t, listeners.Front().Value, Equal(other))
+	Assert(t, listeners.Back().Value, Equal(listener))
+}
@@ -0,0 +1,20 @@
+module github.com/apolloconfig/agollo/v5/tracing/otel
+
+go 1.20
+
+require (
+	github.com/apolloconfig/agollo/v5 v5.0.0-00010101000000-000000000000
+	github.com/tevid/gohamcrest v1.1.1
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel/metric v1.24.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+)
+
+replace github.com/apolloconfig/agollo/v5 => ../..
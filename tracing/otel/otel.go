@@ -0,0 +1,89 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/apolloconfig/agollo/v5/tracing"
+)
+
+// instrumentationName OpenTelemetry instrumentation 名称
+const instrumentationName = "github.com/apolloconfig/agollo/v5"
+
+// Tracer 基于 OpenTelemetry 的链路追踪，通过 extension.SetTracer 设置后生效
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer 使用 provider 创建链路追踪，provider 为 nil 时使用 otel.GetTracerProvider()
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{
+		tracer: provider.Tracer(instrumentationName),
+	}
+}
+
+// Start 开始一个 span
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(toKeyValues(attrs)...))
+	return ctx, &Span{span: span}
+}
+
+// Span OpenTelemetry span
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes 设置 span 属性
+func (s *Span) SetAttributes(attrs ...tracing.Attribute) {
+	s.span.SetAttributes(toKeyValues(attrs)...)
+}
+
+// End 结束 span，err 不为 nil 时记录错误并设置 span 状态为 Error
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func toKeyValues(attrs []tracing.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(attr.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(attr.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(attr.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(attr.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/apolloconfig/agollo/v5/tracing"
+)
+
+var _ tracing.Tracer = (*Tracer)(nil)
+
+func newTestTracer() (*Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return NewTracer(provider), exporter
+}
+
+func TestTracer(t *testing.T) {
+	tracer, exporter := newTestTracer()
+
+	ctx, parent := tracer.Start(context.Background(), tracing.SpanStart,
+		tracing.String(tracing.AppID, "test"),
+		tracing.String(tracing.Cluster, "default"))
+	_, child := tracer.Start(ctx, tracing.SpanRequest, tracing.String(tracing.Namespace, "application"))
+	child.SetAttributes(tracing.Int(tracing.HTTPStatusCode, 200),
+		tracing.Attribute{Key: "int64", Value: int64(1)},
+		tracing.Attribute{Key: "bool", Value: true},
+		tracing.Attribute{Key: "other", Value: 1.5})
+	child.End(nil)
+	parent.End(errors.New("start fail"))
+
+	spans := exporter.GetSpans()
+	Assert(t, len(spans), Equal(2))
+
+	request := spans[0]
+	Assert(t, request.Name, Equal(tracing.SpanRequest))
+	Assert(t, request.Parent.SpanID(), Equal(spans[1].SpanContext.SpanID()))
+	Assert(t, request.Status.Code, Equal(codes.Unset))
+	Assert(t, request.Attributes, Equal([]attribute.KeyValue{
+		attribute.String(tracing.Namespace, "application"),
+		attribute.Int(tracing.HTTPStatusCode, 200),
+		attribute.Int64("int64", 1),
+		attribute.Bool("bool", true),
+		attribute.String("other", "1.5"),
+	}))
+
+	start := spans[1]
+	Assert(t, start.Name, Equal(tracing.SpanStart))
+	Assert(t, start.Status.Code, Equal(codes.Error))
+	Assert(t, start.Status.Description, Equal("start fail"))
+	Assert(t, len(start.Events), Equal(1))
+	Assert(t, start.Attributes, Equal([]attribute.KeyValue{
+		attribute.String(tracing.AppID, "test"),
+		attribute.String(tracing.Cluster, "default"),
+	}))
+}
+
+func TestNewTracerWithGlobalProvider(t *testing.T) {
+	tracer := NewTracer(nil)
+	ctx, span := tracer.Start(context.Background(), tracing.SpanRequest)
+	Assert(t, ctx, NotNilVal())
+	span.End(nil)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import "context"
+
+// span 名称
+const (
+	SpanStart            = "agollo.StartWithConfig"
+	SpanRequest          = "agollo.Request"
+	SpanLoadBackupConfig = "agollo.LoadBackupConfig"
+	SpanUpdateConfig     = "agollo.UpdateConfig"
+	SpanListenerDispatch = "agollo.ListenerDispatch"
+)
+
+// span 属性名
+const (
+	AppID          = "apollo.app_id"
+	Cluster        = "apollo.cluster"
+	Namespace      = "apollo.namespace"
+	ReleaseKey     = "apollo.release_key"
+	API            = "apollo.api"
+	URL            = "http.url"
+	HTTPStatusCode = "http.status_code"
+	Listener       = "apollo.listener"
+)
+
+// Tracer 链路追踪接口，会被多个 goroutine 同时调用，实现需要保证并发安全
+type Tracer interface {
+	// Start 开始一个 span，返回的 ctx 携带该 span，可以作为子 span 的 parent
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span 一次被追踪的操作
+type Span interface {
+	// SetAttributes 设置 span 属性
+	SetAttributes(attrs ...Attribute)
+	// End 结束 span，err 不为 nil 时记录为错误
+	End(err error)
+}
+
+// Attribute span 属性，Value 支持 string、int、int64、bool 类型
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String 创建 string 类型的属性
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int 创建 int 类型的属性
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// NoopTracer 不追踪任何操作，未设置 Tracer 时使用
+type NoopTracer struct {
+}
+
+// Start 返回原 ctx 和不记录任何内容的 span
+func (*NoopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct {
+}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {
+}
+
+func (noopSpan) End(err error) {
+}
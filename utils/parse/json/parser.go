@@ -0,0 +1,101 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+)
+
+const keySeparator = "."
+
+// Parser json转换器
+type Parser struct {
+}
+
+// Parse 内存内容=>json文件转换器
+// 嵌套对象展开为以 . 连接的 key，数组保留为 []interface{}
+func (d *Parser) Parse(configContent interface{}) (map[string]interface{}, error) {
+	content, ok := configContent.(string)
+	if !ok {
+		return nil, nil
+	}
+	if utils.Empty == content {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewBufferString(content))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	// 只允许一个 json 值，其后只能有空白字符
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("json content has trailing data")
+	}
+
+	object, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("json content is not an object")
+	}
+
+	m := make(map[string]interface{})
+	flatten(utils.Empty, object, m)
+	return m, nil
+}
+
+func flatten(prefix string, object map[string]interface{}, m map[string]interface{}) {
+	for key, value := range object {
+		if prefix != utils.Empty {
+			key = prefix + keySeparator + key
+		}
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flatten(key, child, m)
+			continue
+		}
+		m[key] = convertValue(value)
+	}
+}
+
+// convertValue 将 json.Number 转换为 int 或 float64，与 yaml 解析结果保持一致
+func convertValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []interface{}:
+		for i := range v {
+			v[i] = convertValue(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = convertValue(v[key])
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+)
+
+var (
+	jsonParser parse.ContentParser = &Parser{}
+)
+
+func TestJSONParser(t *testing.T) {
+	s, err := jsonParser.Parse(`{
+  "name": "agollo",
+  "db": {
+    "primary": {
+      "host": "127.0.0.1",
+      "port": 3306
+    },
+    "ratio": 0.5,
+    "enabled": true
+  },
+  "empty": {}
+}`)
+	Assert(t, err, NilVal())
+
+	Assert(t, s["name"], Equal("agollo"))
+	Assert(t, s["db.primary.host"], Equal("127.0.0.1"))
+	Assert(t, s["db.primary.port"], Equal(3306))
+	Assert(t, s["db.ratio"], Equal(0.5))
+	Assert(t, s["db.enabled"], Equal(true))
+	Assert(t, s["empty"], Equal(map[string]interface{}{}))
+	Assert(t, len(s), Equal(6))
+}
+
+func TestJSONParserArray(t *testing.T) {
+	s, err := jsonParser.Parse(`{
+  "items": ["a", "b"],
+  "nested": {
+    "numbers": [1, 2.5],
+    "objects": [{"k": 1}]
+  }
+}`)
+	Assert(t, err, NilVal())
+
+	items, ok := s["items"].([]interface{})
+	Assert(t, ok, Equal(true))
+	Assert(t, items, Equal([]interface{}{"a", "b"}))
+
+	numbers, ok := s["nested.numbers"].([]interface{})
+	Assert(t, ok, Equal(true))
+	Assert(t, numbers, Equal([]interface{}{1, 2.5}))
+
+	objects, ok := s["nested.objects"].([]interface{})
+	Assert(t, ok, Equal(true))
+	Assert(t, objects[0], Equal(map[string]interface{}{"k": 1}))
+}
+
+func TestJSONParserOnException(t *testing.T) {
+	s, err := jsonParser.Parse(utils.Empty)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+	s, err = jsonParser.Parse(0)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+
+	s, err = jsonParser.Parse(`{"a":`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+
+	s, err = jsonParser.Parse(`[1, 2]`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+
+	s, err = jsonParser.Parse(`{"a": 1} {"b": 2}`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+
+	s, err = jsonParser.Parse(`{"a": 1}garbage`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+
+	s, err = jsonParser.Parse("{\"a\": 1}\n ")
+	Assert(t, err, NilVal())
+	Assert(t, s, Equal(map[string]interface{}{"a": 1}))
+}
@@ -14,11 +14,181 @@
 
 package properties
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+)
+
+// whitespace properties 中的空白字符
+const whitespace = " \t\f"
+
 // Parser properties转换器
+//
+// 与 java.util.Properties#load 语义一致：
+// 以 # 或 ! 开头的行为注释；行尾奇数个 \ 表示续行，续行的行首空白被忽略；
+// key 与 value 以第一个未转义的 =、: 或空白分隔；
+// 支持 \t \n \r \f \uXXXX 转义，其余 \x 转义为 x；重复的 key 以最后出现的为准
 type Parser struct {
 }
 
 // Parse 内存内容=>properties文件转换器
 func (d *Parser) Parse(configContent interface{}) (map[string]interface{}, error) {
-	return nil, nil
+	content, ok := configContent.(string)
+	if !ok {
+		return nil, nil
+	}
+	if utils.Empty == content {
+		return nil, nil
+	}
+
+	m := make(map[string]interface{})
+	for _, line := range logicalLines(content) {
+		rawKey, rawValue := splitKeyValue(line)
+		key, err := unescape(rawKey)
+		if err != nil {
+			return nil, err
+		}
+		value, err := unescape(rawValue)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// logicalLines 去除空行、注释并合并续行
+func logicalLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	lines := make([]string, 0)
+	var builder strings.Builder
+	continuing := false
+	for _, natural := range strings.Split(content, "\n") {
+		natural = strings.TrimLeft(natural, whitespace)
+		if !continuing && (natural == utils.Empty || natural[0] == '#' || natural[0] == '!') {
+			continue
+		}
+
+		if endsWithEscape(natural) {
+			builder.WriteString(natural[:len(natural)-1])
+			continuing = true
+			continue
+		}
+
+		builder.WriteString(natural)
+		lines = append(lines, builder.String())
+		builder.Reset()
+		continuing = false
+	}
+
+	if continuing {
+		lines = append(lines, builder.String())
+	}
+	return lines
+}
+
+// endsWithEscape 行尾存在奇数个 \ 时为续行
+func endsWithEscape(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func splitKeyValue(line string) (string, string) {
+	escaped := false
+	i := 0
+	for ; i < len(line); i++ {
+		c := line[i]
+		if escaped {
+			escaped = false
+			continue
+		}
+		if c == '\\' {
+			escaped = true
+			continue
+		}
+		if c == '=' || c == ':' || strings.IndexByte(whitespace, c) >= 0 {
+			break
+		}
+	}
+
+	key := line[:i]
+	value := strings.TrimLeft(line[i:], whitespace)
+	if value != utils.Empty && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], whitespace)
+	}
+	return key, value
+}
+
+func unescape(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			builder.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			r, err := parseUnicode(s, i+1)
+			if err != nil {
+				return utils.Empty, err
+			}
+			i += 4
+			// 代理对需要与紧随其后的 \uXXXX 组合
+			if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], "\\u") {
+				if low, err := parseUnicode(s, i+3); err == nil {
+					if combined := utf16.DecodeRune(r, low); combined != utf8.RuneError {
+						r = combined
+						i += 6
+					}
+				}
+			}
+			builder.WriteRune(r)
+		default:
+			builder.WriteByte(s[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+func parseUnicode(s string, start int) (rune, error) {
+	if start+4 > len(s) {
+		return 0, fmt.Errorf("malformed \\uxxxx encoding in %q", s)
+	}
+	v, err := strconv.ParseUint(s[start:start+4], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("malformed \\uxxxx encoding in %q", s)
+	}
+	return rune(v), nil
 }
@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/utils"
 )
 
 var (
@@ -27,5 +29,65 @@ var (
 func TestPropertiesParser(t *testing.T) {
 	s, err := propertiesParser.Parse(`aaaa`)
 	Assert(t, err, NilVal())
+	Assert(t, s["aaaa"], Equal(""))
+
+	s, err = propertiesParser.Parse(`# comment
+! another comment
+   
+key1=value1
+key2 : value2
+key3   value3
+  key4=  value4  
+key5:
+key6==value6
+key1=override`)
+	Assert(t, err, NilVal())
+	Assert(t, len(s), Equal(6))
+	Assert(t, s["key1"], Equal("override"))
+	Assert(t, s["key2"], Equal("value2"))
+	Assert(t, s["key3"], Equal("value3"))
+	Assert(t, s["key4"], Equal("value4  "))
+	Assert(t, s["key5"], Equal(""))
+	Assert(t, s["key6"], Equal("=value6"))
+}
+
+func TestPropertiesParserContinuation(t *testing.T) {
+	s, err := propertiesParser.Parse("fruits = apple, \\\n    banana, \\\r\n  # not a comment\nkey=a\\\\\nnext=b\\\\\\\n  c\r\nlast=\\")
+	Assert(t, err, NilVal())
+	Assert(t, s["fruits"], Equal("apple, banana, # not a comment"))
+	Assert(t, s["key"], Equal("a\\"))
+	Assert(t, s["next"], Equal("b\\c"))
+	Assert(t, s["last"], Equal(""))
+}
+
+func TestPropertiesParserEscape(t *testing.T) {
+	s, err := propertiesParser.Parse(`a\=b\:c\ d=e\tf\ng\\h\x
+unicode=\u4f60\u597d
+emoji=\ud83d\ude00
+high=\ud83d
+`)
+	Assert(t, err, NilVal())
+	Assert(t, s["a=b:c d"], Equal("e\tf\ng\\hx"))
+	Assert(t, s["unicode"], Equal("你好"))
+	Assert(t, s["emoji"], Equal("😀"))
+	Assert(t, s["high"], Equal("\uFFFD"))
+}
+
+func TestPropertiesParserOnException(t *testing.T) {
+	s, err := propertiesParser.Parse(utils.Empty)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+	s, err = propertiesParser.Parse(0)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+	s, err = propertiesParser.Parse("# only comment\n")
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+
+	s, err = propertiesParser.Parse(`key=\u12`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+	s, err = propertiesParser.Parse(`key=\uzzzz`)
+	Assert(t, err, NotNilVal())
 	Assert(t, s, NilVal())
 }
@@ -0,0 +1,88 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+import (
+	gotoml "github.com/pelletier/go-toml"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+)
+
+// Parser toml转换器
+type Parser struct {
+}
+
+// Parse 内存内容=>toml文件转换器
+// table 展开为以 . 连接的 key 并保留 key 的大小写，数组（含 table 数组）保留为 []interface{}
+func (d *Parser) Parse(configContent interface{}) (map[string]interface{}, error) {
+	content, ok := configContent.(string)
+	if !ok {
+		return nil, nil
+	}
+	if utils.Empty == content {
+		return nil, nil
+	}
+
+	tree, err := gotoml.Load(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToMap(tree.ToMap()), nil
+}
+
+// convertToMap 将嵌套的 table 展开为以 . 连接的 key
+func convertToMap(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	flatten(m, utils.Empty, values)
+	return m
+}
+
+func flatten(m map[string]interface{}, prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		if prefix != utils.Empty {
+			key = prefix + "." + key
+		}
+		if table, ok := value.(map[string]interface{}); ok {
+			flatten(m, key, table)
+			continue
+		}
+		m[key] = convertValue(value)
+	}
+}
+
+// convertValue toml 整数解析为 int64，转换为 int 与 yaml 解析结果保持一致
+func convertValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = convertValue(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = convertValue(v[key])
+		}
+		return v
+	default:
+		return v
+	}
+}
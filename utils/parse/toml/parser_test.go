@@ -0,0 +1,116 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+)
+
+var (
+	tomlParser parse.ContentParser = &Parser{}
+)
+
+func TestTOMLParser(t *testing.T) {
+	s, err := tomlParser.Parse(`
+title = "agollo"
+
+[db.primary]
+host = "127.0.0.1"
+port = 3306
+
+[db]
+ratio = 0.5
+enabled = true
+`)
+	Assert(t, err, NilVal())
+
+	Assert(t, s["title"], Equal("agollo"))
+	Assert(t, s["db.primary.host"], Equal("127.0.0.1"))
+	Assert(t, s["db.primary.port"], Equal(3306))
+	Assert(t, s["db.ratio"], Equal(0.5))
+	Assert(t, s["db.enabled"], Equal(true))
+}
+
+func TestTOMLParserArray(t *testing.T) {
+	s, err := tomlParser.Parse(`
+ports = [8001, 8002]
+hosts = ["a", "b"]
+
+[[servers]]
+name = "alpha"
+weight = 1
+
+[[servers]]
+name = "beta"
+weight = 2
+`)
+	Assert(t, err, NilVal())
+
+	Assert(t, s["ports"], Equal([]interface{}{8001, 8002}))
+	Assert(t, s["hosts"], Equal([]interface{}{"a", "b"}))
+
+	servers, ok := s["servers"].([]interface{})
+	Assert(t, ok, Equal(true))
+	Assert(t, len(servers), Equal(2))
+	Assert(t, servers[1], Equal(map[string]interface{}{"name": "beta", "weight": 2}))
+}
+
+func TestTOMLParserOnException(t *testing.T) {
+	s, err := tomlParser.Parse(utils.Empty)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+	s, err = tomlParser.Parse(0)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+
+	s, err = tomlParser.Parse(`a = `)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+
+	m := convertToMap(nil)
+	Assert(t, m, NilVal())
+}
+
+func TestTOMLParserKeyCase(t *testing.T) {
+	s, err := tomlParser.Parse(`
+maxPoolSize = 10
+
+[DB]
+Host = "127.0.0.1"
+`)
+	Assert(t, err, NilVal())
+	Assert(t, s, Equal(map[string]interface{}{"maxPoolSize": 10, "DB.Host": "127.0.0.1"}))
+}
+
+func TestTOMLParserConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s, err := tomlParser.Parse(fmt.Sprintf("key%d = %d", i, i))
+			Assert(t, err, NilVal())
+			Assert(t, s, Equal(map[string]interface{}{fmt.Sprintf("key%d", i): i}))
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+)
+
+const keySeparator = "."
+
+// Parser xml转换器
+//
+// 根元素不参与 key 的拼接，子元素路径以 . 连接，例如 <config><db><host>h</host></db></config> => db.host=h；
+// 属性以 [@name] 追加在所属元素路径之后，例如 <db port="3306"/> => db[@port]=3306，
+// 根元素的属性以根元素名作为路径，例如 <config version="2"> => config[@version]=2；
+// 同级重复出现的元素：若均为无属性、无子元素的叶子节点，值按文档顺序保存为 []interface{}，
+// 否则按出现顺序以下标展开，例如 servers.server.0.host、servers.server.1.host；
+// 所有值均为 string
+type Parser struct {
+}
+
+type node struct {
+	name     string
+	attrs    []xml.Attr
+	text     strings.Builder
+	children []*node
+}
+
+func (n *node) isLeaf() bool {
+	return len(n.attrs) == 0 && len(n.children) == 0
+}
+
+// Parse 内存内容=>xml文件转换器
+func (d *Parser) Parse(configContent interface{}) (map[string]interface{}, error) {
+	content, ok := configContent.(string)
+	if !ok {
+		return nil, nil
+	}
+	if utils.Empty == content {
+		return nil, nil
+	}
+
+	root, err := parseTree(content)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	flatten(utils.Empty, root, m)
+	return m, nil
+}
+
+func parseTree(content string) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewBufferString(content))
+	var root *node
+	stack := make([]*node, 0, 8)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{
+				name:  t.Name.Local,
+				attrs: t.Attr,
+			}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, errors.New("xml content has more than one root element")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("xml content has no root element")
+	}
+	return root, nil
+}
+
+func flatten(prefix string, n *node, m map[string]interface{}) {
+	attrPrefix := prefix
+	if attrPrefix == utils.Empty {
+		attrPrefix = n.name
+	}
+	for _, attr := range n.attrs {
+		m[attrPrefix+"[@"+attr.Name.Local+"]"] = attr.Value
+	}
+
+	text := strings.TrimSpace(n.text.String())
+	if prefix != utils.Empty && (len(n.children) == 0 || text != utils.Empty) {
+		m[prefix] = text
+	}
+
+	names := make([]string, 0, len(n.children))
+	groups := make(map[string][]*node, len(n.children))
+	for _, child := range n.children {
+		if _, ok := groups[child.name]; !ok {
+			names = append(names, child.name)
+		}
+		groups[child.name] = append(groups[child.name], child)
+	}
+
+	for _, name := range names {
+		key := name
+		if prefix != utils.Empty {
+			key = prefix + keySeparator + name
+		}
+
+		group := groups[name]
+		if len(group) == 1 {
+			flatten(key, group[0], m)
+			continue
+		}
+
+		if allLeaf(group) {
+			values := make([]interface{}, 0, len(group))
+			for _, child := range group {
+				values = append(values, strings.TrimSpace(child.text.String()))
+			}
+			m[key] = values
+			continue
+		}
+
+		for i, child := range group {
+			flatten(key+keySeparator+strconv.Itoa(i), child, m)
+		}
+	}
+}
+
+func allLeaf(nodes []*node) bool {
+	for _, n := range nodes {
+		if !n.isLeaf() {
+			return false
+		}
+	}
+	return true
+}
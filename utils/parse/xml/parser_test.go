@@ -0,0 +1,93 @@
+// Copyright 2025 Apollo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"testing"
+
+	. "github.com/tevid/gohamcrest"
+
+	"github.com/apolloconfig/agollo/v5/utils"
+	"github.com/apolloconfig/agollo/v5/utils/parse"
+)
+
+var (
+	xmlParser parse.ContentParser = &Parser{}
+)
+
+func TestXMLParser(t *testing.T) {
+	s, err := xmlParser.Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<config version="2">
+  <name>agollo</name>
+  <db>
+    <primary host="127.0.0.1" port="3306"/>
+    <timeout>10s</timeout>
+  </db>
+  <empty/>
+</config>`)
+	Assert(t, err, NilVal())
+
+	Assert(t, s["config[@version]"], Equal("2"))
+	_, ok := s["[@version]"]
+	Assert(t, ok, Equal(false))
+	Assert(t, s["name"], Equal("agollo"))
+	Assert(t, s["db.primary[@host]"], Equal("127.0.0.1"))
+	Assert(t, s["db.primary[@port]"], Equal("3306"))
+	Assert(t, s["db.primary"], Equal(""))
+	Assert(t, s["db.timeout"], Equal("10s"))
+	Assert(t, s["empty"], Equal(""))
+	_, ok = s["db"]
+	Assert(t, ok, Equal(false))
+}
+
+func TestXMLParserRepeatedElements(t *testing.T) {
+	s, err := xmlParser.Parse(`<config>
+  <hosts>
+    <host>a</host>
+    <host>b</host>
+  </hosts>
+  <servers>
+    <server id="1"><port>80</port></server>
+    <server id="2"><port>81</port></server>
+  </servers>
+</config>`)
+	Assert(t, err, NilVal())
+
+	hosts, ok := s["hosts.host"].([]interface{})
+	Assert(t, ok, Equal(true))
+	Assert(t, hosts, Equal([]interface{}{"a", "b"}))
+
+	Assert(t, s["servers.server.0[@id]"], Equal("1"))
+	Assert(t, s["servers.server.0.port"], Equal("80"))
+	Assert(t, s["servers.server.1[@id]"], Equal("2"))
+	Assert(t, s["servers.server.1.port"], Equal("81"))
+}
+
+func TestXMLParserOnException(t *testing.T) {
+	s, err := xmlParser.Parse(utils.Empty)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+	s, err = xmlParser.Parse(0)
+	Assert(t, err, NilVal())
+	Assert(t, s, NilVal())
+
+	s, err = xmlParser.Parse(`<config><a></config>`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+
+	s, err = xmlParser.Parse(`just text`)
+	Assert(t, err, NotNilVal())
+	Assert(t, s, NilVal())
+}